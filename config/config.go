@@ -7,11 +7,12 @@ Copyright © go-social. All rights reserved.
 package config
 
 import (
+	"crypto/cipher"
 	"encoding/json"
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
-	"io/ioutil"
+	"github.com/emrearmagan/go-social/social"
 )
 
 type (
@@ -20,7 +21,11 @@ type (
 		Dribbble OAuth2Config `json:"dribbble"`
 		Spotify  OAuth2Config `json:"spotify"`
 		Reddit   OAuth2Config `json:"reddit"`
+		// Facebook is kept for existing config files. There is no Facebook provider, so it is not
+		// returned by Account and Accounts.
 		Facebook OAuth2Config `json:"facebook"`
+		Twitch   OAuth2Config `json:"twitch"`
+		Youtube  OAuth2Config `json:"youtube"`
 		Twitter  OAuth1Config `json:"twitter"`
 		Tumblr   OAuth1Config `json:"tumblr"`
 	}
@@ -28,18 +33,91 @@ type (
 	OAuth1Config struct {
 		Token       oauth1.Token      `json:"token"`
 		Credentials oauth.Credentials `json:"credentials"`
+		// UserAgent is sent with every request if set
+		UserAgent string `json:"user_agent,omitempty"`
 	}
 
 	OAuth2Config struct {
 		Token       oauth2.Token      `json:"token"`
 		Credentials oauth.Credentials `json:"credentials"`
+		// UserAgent is sent with every request if set. Required by Reddit
+		UserAgent string `json:"user_agent,omitempty"`
 	}
 )
 
+// entry is an entry of the config, which can be converted to a social.Account.
+type entry interface {
+	account() (social.Account, bool)
+}
+
+// entries returns the entries of the config keyed by the provider name. Only entries of existing providers are returned.
+func (c *Config) entries() map[string]entry {
+	return map[string]entry{
+		"github":   c.Github,
+		"dribbble": c.Dribbble,
+		"spotify":  c.Spotify,
+		"reddit":   c.Reddit,
+		"twitch":   c.Twitch,
+		"youtube":  c.Youtube,
+		"twitter":  c.Twitter,
+		"tumblr":   c.Tumblr,
+	}
+}
+
+// Account returns the entry of the given provider, e.g. "twitter", as social.Account, so the
+// provider can be built with social.New. Returns false if the config has no token for the provider.
+func (c *Config) Account(name string) (social.Account, bool) {
+	e, ok := c.entries()[name]
+	if !ok {
+		return social.Account{}, false
+	}
+	return e.account()
+}
+
+// Accounts returns the entries of all providers with a token as social.Account, keyed by the provider name.
+func (c *Config) Accounts() map[string]social.Account {
+	accounts := make(map[string]social.Account)
+	for name, e := range c.entries() {
+		if account, ok := e.account(); ok {
+			accounts[name] = account
+		}
+	}
+	return accounts
+}
+
+func (c OAuth1Config) account() (social.Account, bool) {
+	if c.Token.Token == "" {
+		return social.Account{}, false
+	}
+	token := c.Token
+	return social.Account{Credentials: c.Credentials, Token: &token, UserAgent: c.UserAgent}, true
+}
+
+func (c OAuth2Config) account() (social.Account, bool) {
+	if c.Token.Token == "" && c.Token.RefreshToken == "" {
+		return social.Account{}, false
+	}
+	token := c.Token
+	return social.Account{Credentials: c.Credentials, Token: &token, UserAgent: c.UserAgent}, true
+}
+
 func LoadConfig(path string) (*Config, error) {
+	return loadConfig(path, nil)
+}
+
+// LoadEncryptedConfig loads a config file written by an encrypted FileStore with the same key.
+func LoadEncryptedConfig(path string, key []byte) (*Config, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return loadConfig(path, aead)
+}
+
+func loadConfig(path string, aead cipher.AEAD) (*Config, error) {
 	var accounts *Config
 
-	content, err := ioutil.ReadFile(path)
+	content, err := readFile(path, aead)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+/*
+config_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOAuth2ConfigRoundTrip(t *testing.T) {
+	expiry := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expiry     time.Time
+		wantExpiry bool
+	}{
+		{"without expiry", time.Time{}, false},
+		{"with expiry", expiry, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := OAuth2Config{
+				Token: oauth2.Token{
+					Token:        "access",
+					RefreshToken: "refresh",
+					TokenType:    "bearer",
+					Expiry:       tt.expiry,
+					Scopes:       []string{"read", "write"},
+				},
+				Credentials: oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"},
+				UserAgent:   "go-social",
+			}
+
+			content, err := json.Marshal(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(content), `"expiry"`); got != tt.wantExpiry {
+				t.Errorf("expiry encoded = %v, want %v: %s", got, tt.wantExpiry, content)
+			}
+
+			var out OAuth2Config
+			if err := json.Unmarshal(content, &out); err != nil {
+				t.Fatal(err)
+			}
+			if !out.Token.Expiry.Equal(in.Token.Expiry) {
+				t.Errorf("Expiry = %v, want %v", out.Token.Expiry, in.Token.Expiry)
+			}
+			out.Token.Expiry, in.Token.Expiry = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(out, in) {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+// TestFileStoreTokenRoundTrip checks that a token saved by a FileStore is loaded unchanged.
+func TestFileStoreTokenRoundTrip(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+	in := &oauth2.Token{Token: "access", RefreshToken: "refresh"}
+	if err := store.Save("spotify", "", in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out oauth2.Token
+	if err := store.Load("spotify", "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&out, in) {
+		t.Errorf("loaded token = %+v, want %+v", out, *in)
+	}
+}
+
+// TestAccountsSkipsFacebook checks that the Facebook entry, which has no provider, is not returned as account.
+func TestAccountsSkipsFacebook(t *testing.T) {
+	cfg := &Config{
+		Github:   OAuth2Config{Token: oauth2.Token{Token: "github"}},
+		Facebook: OAuth2Config{Token: oauth2.Token{Token: "facebook"}},
+	}
+	accounts := cfg.Accounts()
+	if _, ok := accounts["github"]; !ok || len(accounts) != 1 {
+		t.Errorf("accounts = %v, want only github", accounts)
+	}
+	if _, ok := cfg.Account("facebook"); ok {
+		t.Error("Account(facebook) found")
+	}
+}
@@ -0,0 +1,208 @@
+/*
+store.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	tokenKey      = "token"
+	storeFileMode = 0600
+)
+
+// FileStore is an oauth.TokenStore saving the tokens into a JSON file with the same layout as the Config,
+// so a file written by the FileStore can be loaded with LoadConfig. Tokens of named accounts are stored
+// under "provider/account". Other entries of the file, like the credentials, are kept untouched.
+// It is safe for concurrent use, but not for multiple processes writing the same file.
+type FileStore struct {
+	path string
+	// aead encrypts the file if set
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// FileStore implements oauth.TokenStore.
+var _ oauth.TokenStore = (*FileStore)(nil)
+
+// NewFileStore returns a new FileStore for the file at the given path. The file is created on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// NewEncryptedFileStore returns a new FileStore encrypting the file with AES-GCM.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewEncryptedFileStore(path string, key []byte) (*FileStore, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{path: path, aead: aead}, nil
+}
+
+// Load decodes the stored token into the value pointed to by token.
+func (s *FileStore) Load(provider, account string, token interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	data, ok := entries[oauth.StoreKey(provider, account)][tokenKey]
+	if !ok {
+		return oauth.ErrTokenNotFound(provider, account)
+	}
+	return json.Unmarshal(data, token)
+}
+
+// Save stores the given token and writes the file.
+func (s *FileStore) Save(provider, account string, token interface{}) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	key := oauth.StoreKey(provider, account)
+	if entries[key] == nil {
+		entries[key] = make(map[string]json.RawMessage)
+	}
+	entries[key][tokenKey] = data
+	return s.write(entries)
+}
+
+// Delete removes the stored token and writes the file. Entries without any other values are removed entirely.
+func (s *FileStore) Delete(provider, account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	key := oauth.StoreKey(provider, account)
+	entry, ok := entries[key]
+	if !ok {
+		return nil
+	}
+	delete(entry, tokenKey)
+	if len(entry) == 0 {
+		delete(entries, key)
+	}
+	return s.write(entries)
+}
+
+// read returns the entries of the file. A missing file has no entries.
+func (s *FileStore) read() (map[string]map[string]json.RawMessage, error) {
+	entries := make(map[string]map[string]json.RawMessage)
+
+	content, err := readFile(s.path, s.aead)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically, so a crash does not leave a partially written file behind.
+func (s *FileStore) write(entries map[string]map[string]json.RawMessage) error {
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.aead != nil {
+		if content, err = encrypt(s.aead, content); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(storeFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// readFile reads the file at the given path and decrypts it if aead is set.
+func readFile(path string, aead cipher.AEAD) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil || aead == nil {
+		return content, err
+	}
+	return decrypt(aead, content)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt encrypts the plaintext and prepends the random nonce.
+func encrypt(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decrypt decrypts a ciphertext created by encrypt.
+func decrypt(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New(errors.ErrBadRequest, "FileStore: encrypted file is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New(errors.ErrBadAuthenticationData, "FileStore: decrypting file failed: "+err.Error())
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,190 @@
+/*
+store_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestEncryptedFileStore checks that the tokens are encrypted, can be loaded again by the store and
+// LoadEncryptedConfig with the same key, but not with a different key.
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	store, err := NewEncryptedFileStore(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := &oauth2.Token{Token: "access-token", RefreshToken: "refresh-token"}
+	if err := store.Save("spotify", "", in); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("twitter", "work", oauth1.NewToken("token", "token-secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plain := range []string{"access-token", "refresh-token", "spotify", "token-secret"} {
+		if bytes.Contains(content, []byte(plain)) {
+			t.Errorf("file contains %q in plain text", plain)
+		}
+	}
+
+	var out oauth2.Token
+	if err := store.Load("spotify", "", &out); err != nil || !reflect.DeepEqual(&out, in) {
+		t.Errorf("loaded token = %+v, %v, want %+v", out, err, *in)
+	}
+	cfg, err := LoadEncryptedConfig(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Spotify.Token.Token != "access-token" {
+		t.Errorf("token of the config = %q, want access-token", cfg.Spotify.Token.Token)
+	}
+
+	// Every write uses a new nonce
+	if err := store.Save("spotify", "", in); err != nil {
+		t.Fatal(err)
+	}
+	if rewritten, _ := ioutil.ReadFile(path); bytes.Equal(rewritten, content) {
+		t.Error("file is unchanged after saving again, want a new nonce")
+	}
+
+	wrongKey := []byte("fedcba9876543210fedcba9876543210")
+	other, err := NewEncryptedFileStore(path, wrongKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Load("spotify", "", &out); !errors.Is(err, socialErrors.ErrBadAuthenticationData) {
+		t.Errorf("Load with a wrong key = %v, want %v", err, socialErrors.ErrBadAuthenticationData)
+	}
+	// Saving with a wrong key must not overwrite the file
+	if err := other.Save("spotify", "", in); err == nil {
+		t.Error("Save with a wrong key succeeded, want an error")
+	}
+	if _, err := LoadEncryptedConfig(path, wrongKey); !errors.Is(err, socialErrors.ErrBadAuthenticationData) {
+		t.Errorf("LoadEncryptedConfig with a wrong key = %v, want %v", err, socialErrors.ErrBadAuthenticationData)
+	}
+	if err := store.Load("twitter", "work", new(oauth1.Token)); err != nil {
+		t.Errorf("Load after the failed writes = %v", err)
+	}
+}
+
+func TestEncryptedFileStoreKeySize(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		if _, err := NewEncryptedFileStore("tokens.json", testKey[:size]); err != nil {
+			t.Errorf("key of %d bytes: %v", size, err)
+		}
+	}
+	for _, size := range []int{0, 15, 31} {
+		if _, err := NewEncryptedFileStore("tokens.json", testKey[:size]); err == nil {
+			t.Errorf("key of %d bytes succeeded, want an error", size)
+		}
+	}
+}
+
+func TestEncryptedFileStoreTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := ioutil.WriteFile(path, []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewEncryptedFileStore(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Load("spotify", "", new(oauth2.Token)); !errors.Is(err, socialErrors.ErrBadRequest) {
+		t.Errorf("Load of a truncated file = %v, want %v", err, socialErrors.ErrBadRequest)
+	}
+}
+
+// TestFileStorePermissions checks that the file is only readable by the owner, also if it existed before.
+func TestFileStorePermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := ioutil.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewFileStore(path).Save("github", "", &oauth2.Token{Token: "access"}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != storeFileMode {
+		t.Errorf("mode = %v, want %v", mode, os.FileMode(storeFileMode))
+	}
+}
+
+// TestFileStoreAtomicWrite checks that the file is replaced instead of written in place and that no temporary
+// files are left behind. Other entries of the file are kept.
+func TestFileStoreAtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens.json")
+	original := []byte(`{"github":{"credentials":{"consumer_key":"key"}}}`)
+	if err := ioutil.WriteFile(path, original, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A second link to the old file keeps its content if the file is replaced by a rename
+	link := filepath.Join(t.TempDir(), "old.json")
+	if err := os.Link(path, link); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	store := NewFileStore(path)
+	if err := store.Save("github", "", &oauth2.Token{Token: "access"}); err != nil {
+		t.Fatal(err)
+	}
+	if old, _ := ioutil.ReadFile(link); !bytes.Equal(old, original) {
+		t.Errorf("old file = %s, want it untouched", old)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "tokens.json" {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		t.Errorf("files = %v, want only tokens.json", names)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Github.Credentials.ConsumerKey != "key" || cfg.Github.Token.Token != "access" {
+		t.Errorf("github entry = %+v, want the credentials and the saved token", cfg.Github)
+	}
+
+	// Deleting the token keeps the credentials
+	if err := store.Delete("github", ""); err != nil {
+		t.Fatal(err)
+	}
+	var token oauth2.Token
+	if err := store.Load("github", "", &token); !errors.Is(err, socialErrors.ErrNotFound) {
+		t.Errorf("Load after Delete = %+v, %v, want %v", token, err, socialErrors.ErrNotFound)
+	}
+	if cfg, err := LoadConfig(path); err != nil || cfg.Github.Credentials.ConsumerKey != "key" {
+		t.Errorf("config after Delete = %+v, %v, want the credentials", cfg, err)
+	}
+}
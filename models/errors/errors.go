@@ -8,6 +8,8 @@ package errors
 
 import (
 	"errors"
+	"net/http"
+	"time"
 )
 
 var (
@@ -24,18 +26,90 @@ var (
 	ErrUnknownError = errors.New("unknown error")
 )
 
+// SocialError is the error returned by the providers. Errors holds the kind of the error, e.g. ErrRateLimit,
+// so it can be checked with errors.Is(err, errors.ErrRateLimit). The provider error it was mapped from
+// can be retrieved with errors.As, e.g. into a *twitter.APIError.
 type SocialError struct {
+	// Errors is the kind of the error, e.g. ErrRateLimit
 	Errors  error
 	Message string
+
+	// Provider is the name of the provider which returned the error. Empty for errors not returned by an API.
+	Provider string
+	// StatusCode is the http status code of the response
+	StatusCode int
+	// Code is the provider specific error code, e.g. "88" for Twitter or "invalid_grant" for OAuth2 token endpoints
+	Code string
+	// Retryable is true if the request may succeed when sent again, e.g. after a rate limit or a server error
+	Retryable bool
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
+	// Body is the raw body of an error response which could not be decoded
+	Body string
+	// Cause is the provider error the SocialError was mapped from, e.g. a *twitter.APIError
+	Cause error
 }
 
+// New returns a SocialError of the given kind, e.g. ErrRateLimit, with the given message.
 func New(error error, message string) SocialError {
 	return SocialError{
-		Errors:  error,
-		Message: message,
+		Errors:    error,
+		Message:   message,
+		Retryable: Retryable(error, 0),
 	}
 }
 
 func (s SocialError) Error() string {
 	return s.Message
 }
+
+// Unwrap returns the kind and the cause of the error, so both are matched by errors.Is and errors.As.
+func (s SocialError) Unwrap() []error {
+	var errs []error
+	if s.Errors != nil {
+		errs = append(errs, s.Errors)
+	}
+	if s.Cause != nil {
+		errs = append(errs, s.Cause)
+	}
+	return errs
+}
+
+// Is returns true if the target is a SocialError of the same kind.
+func (s SocialError) Is(target error) bool {
+	t, ok := target.(SocialError)
+	return ok && t.Errors == s.Errors
+}
+
+// StatusKind returns the kind of error for the given http status code of an error response.
+// It is used by the providers for responses without a more specific error code.
+func StatusKind(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotModified:
+		return ErrNotModified
+	case statusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimit
+	case statusCode >= 500:
+		return ErrApiError
+	}
+	return ErrUnknownError
+}
+
+// Retryable returns true if a request failing with the given kind of error or http status code may succeed when sent again.
+func Retryable(kind error, statusCode int) bool {
+	return kind == ErrRateLimit || kind == ErrApiError || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// IsRetryable returns true if the given error is a retryable SocialError.
+func IsRetryable(err error) bool {
+	var s SocialError
+	return errors.As(err, &s) && s.Retryable
+}
@@ -0,0 +1,90 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStatusKind(t *testing.T) {
+	tests := []struct {
+		status    int
+		kind      error
+		retryable bool
+	}{
+		{http.StatusNotModified, ErrNotModified, false},
+		{http.StatusBadRequest, ErrBadRequest, false},
+		{http.StatusUnauthorized, ErrUnauthorized, false},
+		{http.StatusForbidden, ErrForbidden, false},
+		{http.StatusNotFound, ErrNotFound, false},
+		{http.StatusConflict, ErrUnknownError, false},
+		{http.StatusTooManyRequests, ErrRateLimit, true},
+		{http.StatusInternalServerError, ErrApiError, true},
+		{http.StatusServiceUnavailable, ErrApiError, true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			kind := StatusKind(tt.status)
+			if kind != tt.kind {
+				t.Errorf("StatusKind(%d) = %v, want %v", tt.status, kind, tt.kind)
+			}
+			if got := Retryable(kind, tt.status); got != tt.retryable {
+				t.Errorf("Retryable(%v, %d) = %v, want %v", kind, tt.status, got, tt.retryable)
+			}
+		})
+	}
+}
+
+type causeError struct{}
+
+func (causeError) Error() string { return "cause" }
+
+func TestSocialErrorMatching(t *testing.T) {
+	err := SocialError{Errors: ErrRateLimit, Message: "slow down", Retryable: true, Cause: causeError{}}
+	wrapped := fmt.Errorf("fetch: %w", err)
+
+	if !errors.Is(wrapped, ErrRateLimit) {
+		t.Error("errors.Is(err, ErrRateLimit) = false")
+	}
+	if !errors.Is(wrapped, New(ErrRateLimit, "other message")) {
+		t.Error("errors.Is(err, SocialError of the same kind) = false")
+	}
+	if errors.Is(wrapped, ErrNotFound) || errors.Is(wrapped, New(ErrNotFound, "")) {
+		t.Error("errors.Is matches another kind")
+	}
+
+	var cause causeError
+	if !errors.As(wrapped, &cause) {
+		t.Error("errors.As(err, cause) = false")
+	}
+	if !IsRetryable(wrapped) {
+		t.Error("IsRetryable(err) = false")
+	}
+	if IsRetryable(errors.New("plain")) {
+		t.Error("IsRetryable(plain error) = true")
+	}
+}
+
+func TestNewRetryable(t *testing.T) {
+	tests := []struct {
+		kind error
+		want bool
+	}{
+		{ErrRateLimit, true},
+		{ErrApiError, true},
+		{ErrForbidden, false},
+		{ErrBadAuthenticationData, false},
+	}
+	for _, tt := range tests {
+		if got := New(tt.kind, "").Retryable; got != tt.want {
+			t.Errorf("New(%v).Retryable = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
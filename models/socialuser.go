@@ -6,7 +6,13 @@ Copyright © go-social. All rights reserved.
 
 package models
 
+import (
+	"time"
+)
+
 type SocialUser struct {
+	// Provider is the name of the provider, e.g. "twitter"
+	Provider     string `json:"provider"`
 	Username     string `json:"username"`
 	Name         string `json:"name"`
 	UserId       string `json:"user_id"`
@@ -16,4 +22,22 @@ type SocialUser struct {
 	Followers    int    `json:"followers"`
 	Following    *int   `json:"following"`
 	Url          string `json:"url"`
+
+	// The following fields are empty if the provider does not provide them
+	Bio       string `json:"bio,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Email     string `json:"email,omitempty"` // Usually requires an additional scope
+	BannerUrl string `json:"banner_url,omitempty"`
+	// CreatedAt is the creation date of the account
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// FetchedAt is the time the user has been fetched from the API
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// OptionalTime returns a pointer to the given time or nil if it is the zero time, e.g. for the CreatedAt of a SocialUser.
+func OptionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
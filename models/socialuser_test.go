@@ -0,0 +1,51 @@
+/*
+socialuser_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSocialUserCreatedAt(t *testing.T) {
+	created := time.Date(2012, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      string
+	}{
+		{"not provided", time.Time{}, ""},
+		{"provided", created, `"created_at":"2012-03-04T05:06:07Z"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := SocialUser{Provider: "spotify", CreatedAt: OptionalTime(tt.createdAt)}
+			content, err := json.Marshal(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := strings.Contains(string(content), "created_at")
+			if tt.want == "" && got {
+				t.Errorf("encoded %s, want no created_at", content)
+			}
+			if tt.want != "" && !strings.Contains(string(content), tt.want) {
+				t.Errorf("encoded %s, want %s", content, tt.want)
+			}
+
+			var decoded SocialUser
+			if err := json.Unmarshal(content, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if (decoded.CreatedAt == nil) != tt.createdAt.IsZero() || (decoded.CreatedAt != nil && !decoded.CreatedAt.Equal(created)) {
+				t.Errorf("decoded CreatedAt = %v, want %v", decoded.CreatedAt, tt.createdAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+authorize.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth1
+
+import (
+	"github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/url"
+)
+
+// defaultCallback is used for out-of-band authorization, e.g. if the user enters the verifier manually
+const defaultCallback = "oob"
+
+// RequestToken obtains a set of temporary credentials (request token and secret) from the providers
+// RequestTokenURL. After authorizing the request token, the user is redirected to the given callbackURL
+// with the oauth_token and oauth_verifier. An empty callbackURL requests out-of-band authorization.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-2.1
+func (a *OAuth1) RequestToken(callbackURL string) (requestToken, requestSecret string, err error) {
+	if callbackURL == "" {
+		callbackURL = defaultCallback
+	}
+
+	values, err := a.tokenRequest(a.endpoint.RequestTokenURL, "", "", map[string]string{
+		oauthCallbackParam: callbackURL,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	if values.Get(oauthCallbackConfirmed) != "true" {
+		return "", "", errors.New(errors.ErrApiError, "OAuth1: oauth_callback_confirmed was not true")
+	}
+	return values.Get(oauthTokenParam), values.Get(oauthTokenSecretParam), nil
+}
+
+// AuthorizationURL returns the url of the providers AuthorizeURL the user must be redirected to
+// for authorizing the given request token.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-2.2
+func (a *OAuth1) AuthorizationURL(requestToken string) (*url.URL, error) {
+	authURL, err := url.Parse(a.endpoint.AuthorizeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	values := authURL.Query()
+	values.Set(oauthTokenParam, requestToken)
+	authURL.RawQuery = values.Encode()
+	return authURL, nil
+}
+
+// AccessToken exchanges the authorized request token and its secret together with the verifier,
+// received on the callback url, for token credentials. The returned token is set as the token
+// of the OAuth1 and all of its copies for further requests and saved into the TokenStore, if any. If saving fails,
+// the token is returned together with the error.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-2.3
+func (a *OAuth1) AccessToken(requestToken, requestSecret, verifier string) (*Token, error) {
+	values, err := a.tokenRequest(a.endpoint.AccessTokenURL, requestToken, requestSecret, map[string]string{
+		oauthVerifierParam: verifier,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token := NewToken(values.Get(oauthTokenParam), values.Get(oauthTokenSecretParam))
+	if token.Token == "" || token.TokenSecret == "" {
+		return nil, errors.New(errors.ErrApiError, "OAuth1: token endpoint returned no token credentials")
+	}
+	return token, a.UpdateToken(token)
+}
+
+// tokenRequest sends a signed POST request to the given token endpoint and returns the form encoded response.
+func (a *OAuth1) tokenRequest(tokenURL, token, tokenSecret string, oauthParams map[string]string) (url.Values, error) {
+	if tokenURL == "" {
+		return nil, errors.New(errors.ErrBadRequest, "OAuth1: missing token endpoint")
+	}
+	if err := a.validCredentials(); err != nil {
+		return nil, err
+	}
+
+	// The tokens are always form encoded, regardless of the Content-Type declared by the provider
+	cl := a.client.Base(tokenURL).Post("").Decoder(client.FormDecoder{}).Sign(func(req *http.Request) error {
+		return a.sign(req, token, tokenSecret, oauthParams)
+	})
+	req, err := cl.Request()
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(a.Context())
+
+	values := url.Values{}
+	apiError := new(TokenError)
+	httpResp, err := cl.Do(req, &values, apiError.ErrorDetail())
+	social.SetResponse(apiError, httpResp)
+	if err := social.CheckError(social.RelevantError(err, apiError)); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
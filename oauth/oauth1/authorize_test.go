@@ -0,0 +1,190 @@
+/*
+authorize_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth1
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newTokenServer returns a server responding to the token endpoints with the given status, Content-Type and body.
+func newTokenServer(status int, contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AuthorizationHeaderName) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func newAuthorizeOAuth(serverURL string) *OAuth1 {
+	cred := &oauth.Credentials{ConsumerKey: photosConsumerKey, ConsumerSecret: photosConsumerSecret}
+	return NewOAuth(context.Background(), cred, nil, client.NewHttpClient()).Endpoint(Endpoint{
+		RequestTokenURL: serverURL + "/request_token",
+		AuthorizeURL:    serverURL + "/authorize",
+		AccessTokenURL:  serverURL + "/access_token",
+	})
+}
+
+// TestTokenRequestContentType checks that the form encoded tokens are decoded regardless of the declared Content-Type.
+func TestTokenRequestContentType(t *testing.T) {
+	for _, contentType := range []string{"application/x-www-form-urlencoded", "text/html; charset=utf-8", ""} {
+		t.Run(contentType, func(t *testing.T) {
+			server := newTokenServer(http.StatusOK, contentType, "oauth_token=request&oauth_token_secret=secret&oauth_callback_confirmed=true")
+			defer server.Close()
+
+			token, secret, err := newAuthorizeOAuth(server.URL).RequestToken("https://app.example.com/callback")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token != "request" || secret != "secret" {
+				t.Errorf("request token = %q/%q, want request/secret", token, secret)
+			}
+		})
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	server := newTokenServer(http.StatusOK, "text/html", "oauth_token=access&oauth_token_secret=access-secret&user_id=1")
+	defer server.Close()
+
+	a := newAuthorizeOAuth(server.URL)
+	store := oauth.NewMemoryStore()
+	a.SetTokenStore(store, "test", "")
+
+	token, err := a.AccessToken("request", "secret", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "access" || token.TokenSecret != "access-secret" {
+		t.Errorf("token = %+v", token)
+	}
+
+	var saved Token
+	if err := store.Load("test", "", &saved); err != nil || saved.Token != "access" {
+		t.Errorf("stored token = %+v, %v, want access", saved, err)
+	}
+}
+
+// failingStore is a TokenStore failing to save.
+type failingStore struct {
+	*oauth.MemoryStore
+}
+
+func (s failingStore) Save(provider, account string, token interface{}) error {
+	return errors.New("disk full")
+}
+
+// TestUpdateTokenSaves checks that the TokenStore is shared by the copies of the OAuth1 and that the token
+// is set even if saving it fails. OAuth1 tokens do not expire, so they are only saved by AccessToken
+// and UpdateToken.
+func TestUpdateTokenSaves(t *testing.T) {
+	server := newTokenServer(http.StatusOK, "text/html", "oauth_token=access&oauth_token_secret=access-secret")
+	defer server.Close()
+
+	a := newAuthorizeOAuth(server.URL)
+	store := oauth.NewMemoryStore()
+	a.SetTokenStore(store, "test", "work")
+
+	// The token obtained by a copy is saved and set for the original
+	if _, err := a.WithContext(context.Background()).AccessToken("request", "secret", "verifier"); err != nil {
+		t.Fatal(err)
+	}
+	var saved Token
+	if err := store.Load("test", "work", &saved); err != nil || saved.Token != "access" || saved.TokenSecret != "access-secret" {
+		t.Errorf("stored token = %+v, %v, want access", saved, err)
+	}
+	if a.Token() == nil || a.Token().Token != "access" {
+		t.Errorf("token = %+v, want access", a.Token())
+	}
+
+	if err := a.UpdateToken(NewToken("updated", "updated-secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Load("test", "work", &saved); err != nil || saved.Token != "updated" {
+		t.Errorf("stored token = %+v, %v, want updated", saved, err)
+	}
+
+	a.SetTokenStore(failingStore{oauth.NewMemoryStore()}, "test", "work")
+	token, err := a.AccessToken("request", "secret", "verifier")
+	if err == nil || token == nil || token.Token != "access" {
+		t.Errorf("AccessToken with a failing store = %+v, %v, want the token and the error", token, err)
+	}
+	if a.Token().Token != "access" {
+		t.Errorf("token = %q after saving failed, want access", a.Token().Token)
+	}
+}
+
+func TestTokenRequestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		kind    error
+		message string
+	}{
+		{"invalid signature", http.StatusUnauthorized, "Could not authenticate you.", socialErrors.ErrBadAuthenticationData, "OAuth1: 401 - Could not authenticate you."},
+		{"json body", http.StatusForbidden, `{"errors":[{"code":415,"message":"Callback URL not approved"}]}`, socialErrors.ErrForbidden, `OAuth1: 403 - {"errors":[{"code":415,"message":"Callback URL not approved"}]}`},
+		{"empty body", http.StatusServiceUnavailable, "", socialErrors.ErrApiError, "OAuth1: 503 - Service Unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTokenServer(tt.status, "text/html", tt.body)
+			defer server.Close()
+
+			_, _, err := newAuthorizeOAuth(server.URL).RequestToken("")
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("error = %v, want kind %v", err, tt.kind)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("message = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+// TestAccessTokenConcurrent obtains token credentials while copies of the OAuth1 sign requests. Run with -race.
+func TestAccessTokenConcurrent(t *testing.T) {
+	server := newTokenServer(http.StatusOK, "text/html", "oauth_token=access&oauth_token_secret=access-secret")
+	defer server.Close()
+
+	a := newAuthorizeOAuth(server.URL)
+	if err := a.UpdateToken(NewToken("old", "old-secret")); err != nil {
+		t.Fatal(err)
+	}
+	copied := a.WithContext(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/me", nil)
+			if err := copied.SignRequest(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	if _, err := a.AccessToken("request", "secret", "verifier"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if copied.Token().Token != "access" {
+		t.Errorf("token of the copy = %q, want access", copied.Token().Token)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+endpoint.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth1
+
+import (
+	"fmt"
+	"github.com/emrearmagan/go-social/models/errors"
+	"net/http"
+	"time"
+)
+
+// Endpoint represents the OAuth1 endpoints of a provider used for obtaining token credentials.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-2
+type Endpoint struct {
+	// RequestTokenURL is the url for obtaining temporary credentials (request token).
+	RequestTokenURL string
+	// AuthorizeURL is the url the user is redirected to for authorizing the request token.
+	AuthorizeURL string
+	// AccessTokenURL is the url for exchanging the authorized request token for token credentials.
+	AccessTokenURL string
+}
+
+// TokenError represents an error response of an OAuth1 token endpoint with its corresponding http StatusCode response
+type TokenError struct {
+	StatusCode int
+	Errors     TokenErrorDetail
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
+}
+
+// TokenErrorDetail represents the actual error response from the token endpoint.
+// Since the providers don't agree on a format, the raw body is kept.
+type TokenErrorDetail struct {
+	Message string
+}
+
+func (e *TokenError) ErrorDetail() interface{} {
+	return &e.Errors
+}
+
+func (e *TokenError) Error() string {
+	if len(e.Errors.Message) > 0 {
+		return fmt.Sprintf("OAuth1: %d - %v", e.StatusCode, e.Errors.Message)
+	}
+	return ""
+}
+
+// Empty returns true if empty. Otherwise, at least 1 error message/code is
+// present and false is returned.
+func (e *TokenError) Empty() bool {
+	return len(e.Errors.Message) == 0
+}
+
+func (e *TokenError) Status() int {
+	return e.StatusCode
+}
+
+func (e *TokenError) SetStatus(code int) {
+	e.StatusCode = code
+}
+
+func (e *TokenError) ReturnErrorResponse() error {
+	switch e.Status() {
+	case 401: // Invalid signature, consumer key, request token or verifier
+		return e.socialError(errors.ErrBadAuthenticationData)
+	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// SetRawBody keeps the body of the error response as message.
+func (e *TokenError) SetRawBody(body []byte) {
+	e.Errors.Message = string(body)
+	if e.Errors.Message == "" {
+		e.Errors.Message = http.StatusText(e.StatusCode)
+	}
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *TokenError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
+// socialError maps the TokenError onto a SocialError of the given kind.
+func (e *TokenError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		StatusCode: e.StatusCode,
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Errors.Message,
+		Cause:      e,
+	}
+}
@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package oauth1
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"fmt"
@@ -14,7 +15,11 @@ import (
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,6 +37,10 @@ const (
 	oauthNonceParam           = "oauth_nonce"
 	oauthSignatureParam       = "oauth_signature"
 	oauthTokenParam           = "oauth_token"
+	oauthTokenSecretParam     = "oauth_token_secret"
+	oauthCallbackParam        = "oauth_callback"
+	oauthCallbackConfirmed    = "oauth_callback_confirmed"
+	oauthVerifierParam        = "oauth_verifier"
 	oauthSignatureMethodParam = "oauth_signature_method"
 	oauthTimestampParam       = "oauth_timestamp"
 	oauthVersionParam         = "oauth_version"
@@ -41,68 +50,151 @@ const (
 type OAuth1 struct {
 	ctx         context.Context
 	credentials *oauth.Credentials
-	token       *Token
 	client      *client.HttpClient
+	// token credentials shared by all copies of the OAuth1
+	tokens *tokenState
 	// OAuth1 signer (defaults is HMAC-SHA1)
 	signer Signer
+	// endpoints for obtaining token credentials
+	endpoint Endpoint
 }
 
-func NewOAuth(ctx context.Context, c *oauth.Credentials, token *Token, cl *client.HttpClient) *OAuth1 {
-	return &OAuth1{
+// Option configures an OAuth1.
+type Option func(*OAuth1)
+
+// WithSigner sets the Signer used for signing the requests, e.g. an RSASigner. Defaults to an HMACSigner.
+func WithSigner(signer Signer) Option {
+	return func(a *OAuth1) {
+		a.signer = signer
+	}
+}
+
+func NewOAuth(ctx context.Context, c *oauth.Credentials, token *Token, cl *client.HttpClient, opts ...Option) *OAuth1 {
+	a := &OAuth1{
 		ctx:         ctx,
 		credentials: c,
-		token:       token,
 		client:      cl,
+		tokens:      newTokenState(token),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(a)
+		}
 	}
+	return a
+}
+
+// clone returns a shallow copy of the OAuth1 object
+func (a *OAuth1) clone() *OAuth1 {
+	c := *a
+	return &c
 }
 
 func (a *OAuth1) NewClient(client *client.HttpClient) *OAuth1 {
-	return &OAuth1{
-		ctx:         a.ctx,
-		credentials: a.credentials,
-		token:       a.token,
-		client:      client,
+	c := a.clone()
+	c.client = client
+	return c
+}
+
+// SetTokenStore sets the TokenStore the token credentials obtained by AccessToken are saved into.
+// The tokens are keyed by the given provider and account. It is shared by all copies of the OAuth1.
+func (a *OAuth1) SetTokenStore(store oauth.TokenStore, provider, account string) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	a.tokens.store = store
+	a.tokens.provider = provider
+	a.tokens.account = account
+}
+
+// WithContext return a new OAuth1 sending the requests with the given context, e.g. a request scoped context
+// carrying a deadline. A nil context keeps the current context.
+func (a *OAuth1) WithContext(ctx context.Context) *OAuth1 {
+	c := a.clone()
+	if ctx != nil {
+		c.ctx = ctx
+	}
+	return c
+}
+
+// Context returns the context the requests are sent with. Defaults to context.Background.
+func (a *OAuth1) Context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
 	}
+	return a.ctx
+}
+
+// Endpoint return a new OAuth1 with the given Endpoint used for obtaining token credentials
+func (a *OAuth1) Endpoint(e Endpoint) *OAuth1 {
+	c := a.clone()
+	c.endpoint = e
+	return c
 }
 
 func (a *OAuth1) Get(path string, resp interface{}, apiError social.ApiErrors, params interface{}) error {
-	client := a.client.AddQuery(params).Get(path)
+	return a.send(a.client.AddQuery(params).Get(path), resp, apiError)
+}
 
-	req, err := a.client.Request()
+// Post sends a POST request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil. Parameters of
+// form bodies are included in the signature.
+func (a *OAuth1) Post(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Post(path).BodyProvider(body), resp, apiError)
+}
+
+// Put sends a PUT request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil. Parameters of
+// form bodies are included in the signature.
+func (a *OAuth1) Put(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Put(path).BodyProvider(body), resp, apiError)
+}
+
+// Patch sends a PATCH request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil. Parameters of
+// form bodies are included in the signature.
+func (a *OAuth1) Patch(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Patch(path).BodyProvider(body), resp, apiError)
+}
+
+// Delete sends a DELETE request with the given body to the given path. The body may be nil.
+func (a *OAuth1) Delete(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Delete(path).BodyProvider(body), resp, apiError)
+}
+
+// send signs and sends the request of the given client.
+func (a *OAuth1) send(cl *client.HttpClient, resp interface{}, apiError social.ApiErrors) error {
+	// The request is signed by the client right before sending it, so that
+	// retries are sent with a fresh nonce and timestamp.
+	client := cl.Sign(a.SignRequest)
+
+	req, err := client.Request()
 	if err != nil {
 		return err
 	}
-	req = req.WithContext(a.ctx)
+	req = req.WithContext(a.Context())
 
-	if err := a.SignRequest(req); err != nil {
-		return err
-	}
 	httpResp, err := client.Do(req, resp, apiError.ErrorDetail())
-	if httpResp != nil {
-		if code := httpResp.StatusCode; code >= 300 {
-			apiError.SetStatus(httpResp.StatusCode)
-		}
-	}
-	return social.RelevantError(err, apiError)
+	social.SetResponse(apiError, httpResp)
+	err = social.RelevantError(err, apiError)
+	client.RecordError(req.Context(), social.CheckError(err))
+	return err
 }
 
-// oauthParams returns the OAuth request parameters for the given credentials,
-// method, URL and application params. See
-// http://tools.ietf.org/html/rfc5849#section-3.4 for more information about
+// oauthParams returns the OAuth protocol parameters for the given credentials and token.
+// See http://tools.ietf.org/html/rfc5849#section-3.4 for more information about
 // signatures.
-func (a *OAuth1) oAuthParams(req *http.Request) map[string]string {
+func (a *OAuth1) oAuthParams(token string) map[string]string {
 	params := map[string]string{
 		oauthConsumerKeyParam:     a.credentials.ConsumerKey,
-		oauthTokenParam:           a.token.Token,
 		oauthNonceParam:           getNonce(),
 		oauthSignatureMethodParam: a.Signer().Name(),
 		oauthTimestampParam:       strconv.FormatInt(time.Now().Unix(), 10), //"1318622958",
 		oauthVersionParam:         defaultOauthVersion,
 	}
-
-	/// add request query
-	for key, value := range req.URL.Query() {
-		params[key] = value[0]
+	// Requesting temporary credentials is done without a token
+	if token != "" {
+		params[oauthTokenParam] = token
 	}
 
 	return params
@@ -112,23 +204,54 @@ func (a *OAuth1) Client() *client.HttpClient {
 	return a.client
 }
 
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (a *OAuth1) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return a.client.RateLimit(endpoint)
+}
+
 func (a *OAuth1) SignRequest(req *http.Request) error {
-	if a.credentials.ConsumerKey == "" || a.credentials.ConsumerSecret == "" {
-		return errors.New(errors.ErrBadAuthenticationData, "OAuth1: provide valid credentials")
+	if err := a.validCredentials(); err != nil {
+		return err
 	}
-	if a.token.Token == "" || a.token.TokenSecret == "" {
+	token := a.Token()
+	if token == nil || token.Token == "" || (token.TokenSecret == "" && usesSecrets(a.Signer())) {
 		return errors.New(errors.ErrBadAuthenticationData, "OAuth1: provide valid token")
 	}
 
-	oauthParams := a.oAuthParams(req)
+	return a.sign(req, token.Token, token.TokenSecret, nil)
+}
+
+// validCredentials returns an error if the consumer key is missing or the consumer secret is
+// missing for a signer signing with it.
+func (a *OAuth1) validCredentials() error {
+	if a.credentials.ConsumerKey == "" || (a.credentials.ConsumerSecret == "" && usesSecrets(a.Signer())) {
+		return errors.New(errors.ErrBadAuthenticationData, "OAuth1: provide valid credentials")
+	}
+	return nil
+}
+
+// sign signs the request with the given token credentials. The additional OAuth params
+// such as oauth_callback or oauth_verifier are included in the signature.
+func (a *OAuth1) sign(req *http.Request, token, tokenSecret string, additional map[string]string) error {
+	oauthParams := a.oAuthParams(token)
+	for k, v := range additional {
+		oauthParams[k] = v
+	}
+
+	params, err := requestParams(req)
+	if err != nil {
+		return err
+	}
 
 	//Signature Base
-	signatureBase := signatureBase(req, oauthParams)
+	signatureBase := signatureBase(req, oauthParams, params)
 
 	//Sign
-	signature, err := a.Signer().Sign(a.token.TokenSecret, signatureBase)
+	signature, err := a.Signer().Sign(tokenSecret, signatureBase)
 	if err != nil {
-		return errors.New(errors.ErrBadAuthenticationData, "OAuth1: failed to sign message")
+		signErr := errors.New(errors.ErrBadAuthenticationData, fmt.Sprintf("OAuth1: failed to sign message: %v", err))
+		signErr.Cause = err
+		return signErr
 	}
 
 	oauthParams[oauthSignatureParam] = signature
@@ -137,6 +260,53 @@ func (a *OAuth1) SignRequest(req *http.Request) error {
 	return nil
 }
 
+// requestParams returns the query parameters and the form encoded body parameters of the request.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.1.3.1
+func requestParams(req *http.Request) (url.Values, error) {
+	params := req.URL.Query()
+
+	body, err := bodyParams(req)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range body {
+		params[key] = append(params[key], values...)
+	}
+	return params, nil
+}
+
+// bodyParams returns the parameters of a form encoded request body, which must be included in the signature.
+// Bodies of other content types are not part of the signature.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.1.3.1
+func bodyParams(req *http.Request) (url.Values, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get(client.ContentTypeHeader)); mediaType != client.ContentTypeForm {
+		return nil, nil
+	}
+
+	var body []byte
+	var err error
+	if req.GetBody != nil {
+		// Read a copy, so the body of the request is left untouched
+		var rc io.ReadCloser
+		if rc, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		body, err = ioutil.ReadAll(rc)
+	} else {
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return url.ParseQuery(string(body))
+}
+
 // authHeaderValue formats OAuth parameters according to RFC 5849 3.5.1. OAuth
 // params are percent encoded, sorted by key (for testability), and joined by
 // "=" into pairs. Pairs are joined with a ", " comma separator into a header
@@ -147,6 +317,17 @@ func authHeaderValue(oauthParams map[string]string) string {
 	return authorizationPrefix + strings.Join(pairs, ",")
 }
 
+// Token returns the token credentials used for signing the requests.
+func (a *OAuth1) Token() *Token {
+	return a.tokens.get()
+}
+
+// UpdateToken sets the token credentials for all copies of the OAuth1 and saves them into the TokenStore, if any.
+// The token is set even if saving fails.
+func (a *OAuth1) UpdateToken(token *Token) error {
+	return a.tokens.set(token)
+}
+
 func (a *OAuth1) Signer() Signer {
 	if a.signer != nil {
 		return a.signer
@@ -155,10 +336,13 @@ func (a *OAuth1) Signer() Signer {
 	return &HMACSigner{ConsumerSecret: a.credentials.ConsumerSecret}
 }
 
-func signatureBase(req *http.Request, params map[string]string) string {
+// signatureBase returns the signature base string of the request for the given OAuth protocol
+// parameters and request parameters.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.1
+func signatureBase(req *http.Request, oauthParams map[string]string, params url.Values) string {
 	method := strings.ToUpper(req.Method)
 	baseURL := baseURI(req)
-	paramString := strings.Join(sortParameters(encodeParameters(params), "%s=%s"), "&")
+	paramString := normalizeParameters(oauthParams, params)
 
 	//siganture base string consutrcted accoirding to 3.4.1
 	baseParts := []string{method, PercentEncode(baseURL), PercentEncode(paramString)}
@@ -183,6 +367,34 @@ func baseURI(req *http.Request) string {
 	return fmt.Sprintf("%v://%v%v", scheme, host, path)
 }
 
+// normalizeParameters percent encodes the OAuth protocol parameters and request parameters, sorts them
+// by name and, for parameters with the same name, by value and joins them into the normalized parameter string.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.1.3.2
+func normalizeParameters(oauthParams map[string]string, params url.Values) string {
+	pairs := make([][2]string, 0, len(oauthParams)+len(params))
+	for key, value := range oauthParams {
+		pairs = append(pairs, [2]string{PercentEncode(key), PercentEncode(value)})
+	}
+	for key, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{PercentEncode(key), PercentEncode(value)})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	joined := make([]string, len(pairs))
+	for i, pair := range pairs {
+		joined[i] = pair[0] + "=" + pair[1]
+	}
+	return strings.Join(joined, "&")
+}
+
 // encodeParameters percent encodes parameter keys and values according to
 // RFC5849 3.6 and RFC3986 2.1 and returns a new map.
 func encodeParameters(params map[string]string) map[string]string {
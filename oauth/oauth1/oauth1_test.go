@@ -0,0 +1,280 @@
+/*
+oauth1_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Credentials of the example of RFC 5849 section 3.4.1. The RFC does not give the secrets.
+const (
+	exampleConsumerKey    = "9djdj82h48djs9d2"
+	exampleConsumerSecret = "j49sj3j29djd"
+	exampleToken          = "kkk9d7dh3k39sjv7"
+	exampleTokenSecret    = "dh893hdasih9"
+)
+
+// testAPIError is a minimal social.ApiErrors for the requests of the tests.
+type testAPIError struct {
+	StatusCode int
+	Errors     struct {
+		Message string `json:"message"`
+	}
+}
+
+func (e *testAPIError) ErrorDetail() interface{}   { return &e.Errors }
+func (e *testAPIError) Error() string              { return e.Errors.Message }
+func (e *testAPIError) Empty() bool                { return e.Errors.Message == "" }
+func (e *testAPIError) Status() int                { return e.StatusCode }
+func (e *testAPIError) SetStatus(code int)         { e.StatusCode = code }
+func (e *testAPIError) ReturnErrorResponse() error { return e }
+
+func newExampleOAuth(cl *client.HttpClient) *OAuth1 {
+	cred := &oauth.Credentials{ConsumerKey: exampleConsumerKey, ConsumerSecret: exampleConsumerSecret}
+	return NewOAuth(context.Background(), cred, NewToken(exampleToken, exampleTokenSecret), cl)
+}
+
+// authParams returns the decoded parameters of the Authorization header of the request.
+// It reports malformed headers with t.Errorf, so it can be called by the handlers of test servers.
+func authParams(t *testing.T, req *http.Request) map[string]string {
+	t.Helper()
+	header := req.Header.Get(AuthorizationHeaderName)
+	if !strings.HasPrefix(header, authorizationPrefix) {
+		t.Errorf("Authorization = %q, want the OAuth scheme", header)
+		return nil
+	}
+
+	params := make(map[string]string)
+	for _, pair := range strings.Split(strings.TrimPrefix(header, authorizationPrefix), ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			t.Errorf("malformed Authorization parameter %q", pair)
+			return nil
+		}
+		value, err := url.PathUnescape(strings.Trim(parts[1], `"`))
+		if err != nil {
+			t.Error(err)
+			return nil
+		}
+		params[parts[0]] = value
+	}
+	return params
+}
+
+// hmacSHA1 returns the HMAC-SHA1 signature of the base string with the secrets of the example.
+func hmacSHA1(base string) string {
+	mac := hmac.New(sha1.New, []byte(exampleConsumerSecret+"&"+exampleTokenSecret))
+	mac.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TestSignRequestFormBody signs the request of RFC 5849 section 3.4.1.1 built with a client.FormBody and
+// checks the signature against the base string of the RFC. The parameters of the form body must be part
+// of the signature and the body must be sent unchanged.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.1.3
+func TestSignRequestFormBody(t *testing.T) {
+	query := struct {
+		B5 string `url:"b5"`
+		A3 string `url:"a3"`
+		C  string `url:"c@"`
+		A2 string `url:"a2"`
+	}{B5: "=%3D", A3: "a", A2: "r b"}
+	cl := client.NewHttpClient().Base("http://example.com").
+		AddQuery(query).
+		Post("/request").
+		BodyProvider(client.FormBody(url.Values{"c2": {""}, "a3": {"2 q"}}))
+	req, err := cl.Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newExampleOAuth(client.NewHttpClient()).SignRequest(req); err != nil {
+		t.Fatal(err)
+	}
+
+	params := authParams(t, req)
+	base := "POST&http%3A%2F%2Fexample.com%2Frequest&a2%3Dr%2520b%26a3%3D2%2520q" +
+		"%26a3%3Da%26b5%3D%253D%25253D%26c%2540%3D%26c2%3D%26oauth_consumer_" +
+		"key%3D9djdj82h48djs9d2%26oauth_nonce%3D" + params[oauthNonceParam] +
+		"%26oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D" + params[oauthTimestampParam] +
+		"%26oauth_token%3Dkkk9d7dh3k39sjv7%26oauth_version%3D1.0"
+	if want := hmacSHA1(base); params[oauthSignatureParam] != want {
+		t.Errorf("oauth_signature = %q, want %q", params[oauthSignatureParam], want)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a3=2+q&c2="; string(body) != want {
+		t.Errorf("body = %q after signing, want %q", body, want)
+	}
+}
+
+func TestBodyParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		getBody     bool
+		want        url.Values
+	}{
+		{"form", client.ContentTypeForm, true, url.Values{"status": {"hello world"}}},
+		{"form with charset", client.ContentTypeForm + "; charset=utf-8", true, url.Values{"status": {"hello world"}}},
+		// The body is buffered and replaced, so it can still be sent
+		{"form without GetBody", client.ContentTypeForm, false, url.Values{"status": {"hello world"}}},
+		{"json", client.ContentTypeJSON, true, nil},
+		{"no content type", "", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://example.com/statuses", strings.NewReader("status=hello+world"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.getBody {
+				req.Body = ioutil.NopCloser(strings.NewReader("status=hello+world"))
+				req.GetBody = nil
+			}
+			req.Header.Set(client.ContentTypeHeader, tt.contentType)
+
+			got, err := bodyParams(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) || got.Encode() != tt.want.Encode() {
+				t.Errorf("bodyParams() = %v, want %v", got, tt.want)
+			}
+			if body, _ := ioutil.ReadAll(req.Body); string(body) != "status=hello+world" {
+				t.Errorf("body = %q, want it untouched", body)
+			}
+		})
+	}
+}
+
+type searchParams struct {
+	Query string `url:"q"`
+}
+
+// TestRequestMethods sends a request with every method and body type and checks that the server receives
+// the method, the body and a valid signature covering the form body.
+func TestRequestMethods(t *testing.T) {
+	var got struct {
+		method, contentType, body string
+		signed                    bool
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request as the client signed it
+		r.URL.Scheme, r.URL.Host = "http", r.Host
+		params, err := requestParams(r)
+		if err != nil {
+			t.Error(err)
+		}
+		auth := authParams(t, r)
+		oauthParams := make(map[string]string)
+		for key, value := range auth {
+			if key != oauthSignatureParam {
+				oauthParams[key] = value
+			}
+		}
+		got.signed = auth != nil && hmacSHA1(signatureBase(r, oauthParams, params)) == auth[oauthSignatureParam]
+
+		body, _ := ioutil.ReadAll(r.Body)
+		got.method, got.contentType, got.body = r.Method, r.Header.Get(client.ContentTypeHeader), string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	a := newExampleOAuth(client.NewHttpClient().Base(server.URL))
+	form := client.FormBody(url.Values{"status": {"hello world"}})
+	json := client.JSONBody(map[string]string{"status": "hello world"})
+	tests := []struct {
+		name        string
+		send        func(resp interface{}) error
+		method      string
+		contentType string
+		body        string
+	}{
+		{"get", func(resp interface{}) error {
+			return a.Get("/statuses", resp, new(testAPIError), searchParams{Query: "a b"})
+		}, http.MethodGet, "", ""},
+		{"post form", func(resp interface{}) error { return a.Post("/statuses", form, resp, new(testAPIError), nil) }, http.MethodPost, client.ContentTypeForm, "status=hello+world"},
+		{"post json", func(resp interface{}) error { return a.Post("/statuses", json, resp, new(testAPIError), nil) }, http.MethodPost, client.ContentTypeJSON, `{"status":"hello world"}` + "\n"},
+		{"put form", func(resp interface{}) error { return a.Put("/statuses/1", form, resp, new(testAPIError), nil) }, http.MethodPut, client.ContentTypeForm, "status=hello+world"},
+		{"patch json", func(resp interface{}) error { return a.Patch("/statuses/1", json, resp, new(testAPIError), nil) }, http.MethodPatch, client.ContentTypeJSON, `{"status":"hello world"}` + "\n"},
+		{"delete", func(resp interface{}) error { return a.Delete("/statuses/1", nil, resp, new(testAPIError), nil) }, http.MethodDelete, "", ""},
+		{"delete form", func(resp interface{}) error { return a.Delete("/statuses/1", form, resp, new(testAPIError), nil) }, http.MethodDelete, client.ContentTypeForm, "status=hello+world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp struct {
+				ID string `json:"id"`
+			}
+			if err := tt.send(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ID != "1" {
+				t.Errorf("response id = %q, want 1", resp.ID)
+			}
+			if got.method != tt.method || got.contentType != tt.contentType || got.body != tt.body {
+				t.Errorf("server received %s %q with Content-Type %q, want %s %q with %q", got.method, got.body, got.contentType, tt.method, tt.body, tt.contentType)
+			}
+			if !got.signed {
+				t.Error("signature does not match the request")
+			}
+		})
+	}
+}
+
+// TestWithContext checks that the requests are sent with the context of WithContext instead of the one of
+// NewOAuth, which is only the fallback, and that canceling it aborts a request in flight.
+func TestWithContext(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			arrived <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cred := &oauth.Credentials{ConsumerKey: exampleConsumerKey, ConsumerSecret: exampleConsumerSecret}
+	a := NewOAuth(canceled, cred, NewToken(exampleToken, exampleTokenSecret), client.NewHttpClient().Base(server.URL))
+
+	if err := a.Get("/me", nil, new(testAPIError), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with the context of NewOAuth = %v, want %v", err, context.Canceled)
+	}
+	if err := a.WithContext(context.Background()).Get("/me", nil, new(testAPIError), nil); err != nil {
+		t.Errorf("error with the context of WithContext = %v, want none", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		cancel()
+	}()
+	a = NewOAuth(context.Background(), cred, NewToken(exampleToken, exampleTokenSecret), client.NewHttpClient().Base(server.URL))
+	if err := a.WithContext(ctx).Post("/slow", nil, nil, new(testAPIError), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error of the canceled request = %v, want %v", err, context.Canceled)
+	}
+}
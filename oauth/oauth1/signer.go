@@ -12,7 +12,9 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
+	"github.com/emrearmagan/go-social/models/errors"
 	"hash"
 	"strings"
 )
@@ -26,12 +28,16 @@ type Signer interface {
 }
 
 const (
-	HMACSHA1  = "HMAC-SHA1"
-	RSASHA1   = "RSA-SHA1"
-	PLAINTEXT = "Plain text"
+	HMACSHA1   = "HMAC-SHA1"
+	HMACSHA256 = "HMAC-SHA256"
+	RSASHA1    = "RSA-SHA1"
+	PLAINTEXT  = "PLAINTEXT"
 )
 
-//HMAC
+// HMAC
+
+// HMACSigner signs messages with an HMAC-SHA1 digest, using the consumer secret and token secret as key.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.2
 type HMACSigner struct {
 	ConsumerSecret string
 }
@@ -43,28 +49,52 @@ func hmacSign(signingKey, message string, algo func() hash.Hash) (string, error)
 	return base64.StdEncoding.EncodeToString(signatureBytes), nil
 }
 
+// signingKey returns the encoded consumer secret and token secret joined by "&".
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.2
+func signingKey(consumerSecret, tokenSecret string) string {
+	return strings.Join([]string{PercentEncode(consumerSecret), PercentEncode(tokenSecret)}, "&")
+}
+
 func (s *HMACSigner) Sign(key, message string) (string, error) {
 	signingKey := s.Key(key)
 	return hmacSign(signingKey, message, sha1.New)
-
 }
 
-// Name returns the HMAC-SHA256 method.
+// Name returns the HMAC-SHA1 method.
 func (s *HMACSigner) Name() string {
 	return HMACSHA1
 }
 
-// Name returns the HMAC-SHA256 method.
+// Key returns the signing key for the given token secret.
 func (s *HMACSigner) Key(token string) string {
-	return strings.Join([]string{s.ConsumerSecret, token}, "&")
+	return signingKey(s.ConsumerSecret, token)
+}
+
+// HMACSHA256Signer signs messages with an HMAC-SHA256 digest, using the consumer secret and token secret as key.
+// HMAC-SHA256 is not part of RFC 5849, but supported by several providers.
+type HMACSHA256Signer struct {
+	ConsumerSecret string
+}
+
+func (s *HMACSHA256Signer) Sign(key, message string) (string, error) {
+	return hmacSign(s.Key(key), message, sha256.New)
+}
+
+// Name returns the HMAC-SHA256 method.
+func (s *HMACSHA256Signer) Name() string {
+	return HMACSHA256
+}
 
+// Key returns the signing key for the given token secret.
+func (s *HMACSHA256Signer) Key(token string) string {
+	return signingKey(s.ConsumerSecret, token)
 }
 
 // RSA
-//TODO if needed
 
 // RSASigner RSA PKCS1-v1_5 signs SHA1 digests of messages using the given
 // RSA private key.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.3
 type RSASigner struct {
 	PrivateKey *rsa.PrivateKey
 }
@@ -75,8 +105,11 @@ func (s *RSASigner) Name() string {
 }
 
 // Sign uses RSA PKCS1-v1_5 to sign a SHA1 digest of the given message. The
-// tokenSecret is not used with this signing scheme.
-func (s *RSASigner) Sign(message string) (string, error) {
+// token secret is not used with this signing scheme.
+func (s *RSASigner) Sign(_, message string) (string, error) {
+	if s.PrivateKey == nil {
+		return "", errors.New(errors.ErrBadAuthenticationData, "OAuth1: RSASigner requires a private key")
+	}
 	digest := sha1.Sum([]byte(message))
 	signature, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA1, digest[:])
 	if err != nil {
@@ -84,3 +117,27 @@ func (s *RSASigner) Sign(message string) (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(signature), nil
 }
+
+// usesSecrets returns true if the signer signs with the consumer secret and token secret.
+// RSA-SHA1 signs with the private key of the consumer instead.
+func usesSecrets(s Signer) bool {
+	return s.Name() != RSASHA1
+}
+
+// Plaintext
+
+// PlaintextSigner uses the consumer secret and token secret as signature. The message is not used.
+// Since the secrets are sent in the clear, it must only be used over HTTPS.
+// See https://datatracker.ietf.org/doc/html/rfc5849#section-3.4.4
+type PlaintextSigner struct {
+	ConsumerSecret string
+}
+
+func (s *PlaintextSigner) Sign(key, _ string) (string, error) {
+	return signingKey(s.ConsumerSecret, key), nil
+}
+
+// Name returns the PLAINTEXT method.
+func (s *PlaintextSigner) Name() string {
+	return PLAINTEXT
+}
@@ -6,6 +6,11 @@ Copyright © go-social. All rights reserved.
 
 package oauth1
 
+import (
+	"github.com/emrearmagan/go-social/oauth"
+	"sync"
+)
+
 // Token represents an OAuth1 AccessToken (token credentials) and secret
 type Token struct {
 	Token       string `json:"access_token"`
@@ -19,3 +24,39 @@ func NewToken(token, tokenSecret string) *Token {
 		TokenSecret: tokenSecret,
 	}
 }
+
+// tokenState holds the token credentials shared by an OAuth1 object and all of its copies, so that
+// the token obtained by AccessToken is used by every service of a client.
+type tokenState struct {
+	mu    sync.RWMutex
+	token *Token
+
+	// store persists obtained token credentials if set
+	store    oauth.TokenStore
+	provider string
+	account  string
+}
+
+func newTokenState(token *Token) *tokenState {
+	return &tokenState{token: token}
+}
+
+func (s *tokenState) get() *Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token
+}
+
+// set replaces the token and saves it into the TokenStore, if any.
+func (s *tokenState) set(token *Token) error {
+	s.mu.Lock()
+	s.token = token
+	store, provider, account := s.store, s.provider, s.account
+	s.mu.Unlock()
+
+	if token != nil && store != nil {
+		return store.Save(provider, account, token)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+authcode.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"net/url"
+	"strings"
+)
+
+const stateLength = 32
+
+// AuthCodeOption adds provider specific parameters to the authorization url.
+type AuthCodeOption func(v url.Values)
+
+// SetAuthURLParam sets the given key value pair in the authorization url,
+// e.g. SetAuthURLParam("duration", "permanent") for Reddit or SetAuthURLParam("access_type", "offline") for YouTube.
+func SetAuthURLParam(key, value string) AuthCodeOption {
+	return func(v url.Values) {
+		v.Set(key, value)
+	}
+}
+
+// AuthCodeURL returns the url of the providers consent page the user must be redirected to for granting access.
+// The state is returned unchanged to the redirect url and should be validated to protect against CSRF.
+// If pkce is non-nil, the code challenge is added and the PKCE verifier must be passed to Exchange.
+// See https://datatracker.ietf.org/doc/html/rfc6749#section-4.1.1
+func (a *OAuth2) AuthCodeURL(state string, scopes []string, pkce *PKCE, opts ...AuthCodeOption) string {
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {a.credentials.ConsumerKey},
+	}
+	if a.redirectURL != "" {
+		v.Set("redirect_uri", a.redirectURL)
+	}
+	if len(scopes) > 0 {
+		v.Set("scope", strings.Join(scopes, " "))
+	}
+	if state != "" {
+		v.Set("state", state)
+	}
+	if pkce != nil {
+		v.Set("code_challenge", pkce.Challenge)
+		v.Set("code_challenge_method", pkce.Method)
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if strings.Contains(a.endpoint.AuthURL, "?") {
+		return a.endpoint.AuthURL + "&" + v.Encode()
+	}
+	return a.endpoint.AuthURL + "?" + v.Encode()
+}
+
+// NewState returns a random state for AuthCodeURL. It must be kept, e.g. in the session of the user,
+// and passed to ExchangeRedirect.
+func NewState() (string, error) {
+	b := make([]byte, stateLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ExchangeRedirect checks the query of the redirect url and exchanges its code for a token like Exchange.
+// The state of the query must match the given state, which was passed to AuthCodeURL. An error sent
+// by the authorization server instead of a code, e.g. if the user denied the access, is returned as TokenError.
+// See https://datatracker.ietf.org/doc/html/rfc6749#section-4.1.2
+func (a *OAuth2) ExchangeRedirect(ctx context.Context, query url.Values, state string, verifier string) (*Token, error) {
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 {
+		return nil, errors.New(errors.ErrBadRequest, "OAuth2: state of the redirect does not match")
+	}
+	if query.Get("error") != "" {
+		apiError := &TokenError{Errors: TokenErrorDetail{Error: query.Get("error"), ErrorDescription: query.Get("error_description")}}
+		return nil, apiError.ReturnErrorResponse()
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return nil, errors.New(errors.ErrBadRequest, "OAuth2: redirect contains no code")
+	}
+	return a.Exchange(ctx, code, verifier)
+}
+
+// Exchange exchanges the authorization code received on the redirect url for a token.
+// The verifier is the PKCE code verifier and should be left empty if no PKCE was used.
+// The returned token is set as the token of the OAuth2 for further requests and saved into the TokenStore, if any.
+// If saving fails, the token is returned together with the error.
+// See https://datatracker.ietf.org/doc/html/rfc6749#section-4.1.3
+func (a *OAuth2) Exchange(ctx context.Context, code string, verifier string) (*Token, error) {
+	params := url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {code},
+	}
+	if a.redirectURL != "" {
+		params.Set("redirect_uri", a.redirectURL)
+	}
+	if verifier != "" {
+		params.Set("code_verifier", verifier)
+	}
+
+	token, err := a.retrieveToken(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return token, a.UpdateToken(token)
+}
+
+// retrieveToken requests a token from the token endpoint with the given parameters.
+// Client credentials are sent according to the AuthStyle of the Endpoint. Public clients
+// without a client secret always send the client id in the parameters.
+func (a *OAuth2) retrieveToken(ctx context.Context, params url.Values) (*Token, error) {
+	if a.endpoint.TokenURL == "" {
+		return nil, errors.New(errors.ErrBadRequest, "OAuth2: missing token endpoint")
+	}
+	if a.credentials.ConsumerKey == "" {
+		return nil, errors.New(errors.ErrBadAuthenticationData, "OAuth2: provide valid credentials")
+	}
+
+	basic := a.endpoint.AuthStyle == AuthStyleInHeader && a.credentials.ConsumerSecret != ""
+	if !basic {
+		params.Set("client_id", a.credentials.ConsumerKey)
+		if a.credentials.ConsumerSecret != "" {
+			params.Set("client_secret", a.credentials.ConsumerSecret)
+		}
+	}
+
+	cl := a.client.Base(a.endpoint.TokenURL).Post("").BodyForm(params)
+	// GitHub responds with form values unless JSON is accepted explicitly
+	cl = cl.Set("Accept", "application/json")
+	if basic {
+		signer := BasicSigner{ConsumerKey: a.credentials.ConsumerKey, ConsumerSecret: a.credentials.ConsumerSecret}
+		cl = cl.Set(AuthorizationHeaderName, signer.authorizationHeaderValue())
+	}
+
+	req, err := cl.Request()
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	tokenResp := new(tokenResponse)
+	apiError := new(TokenError)
+	httpResp, err := cl.Do(req, tokenResp, apiError.ErrorDetail())
+	social.SetResponse(apiError, httpResp)
+	if err := social.CheckError(social.RelevantError(err, apiError)); err != nil {
+		return nil, err
+	}
+
+	// Some providers like GitHub respond with 200 even if the exchange failed
+	if tokenResp.Error != "" {
+		apiError.Errors = TokenErrorDetail{Error: tokenResp.Error, ErrorDescription: tokenResp.ErrorDescription}
+		apiError.SetStatus(httpResp.StatusCode)
+		return nil, apiError.ReturnErrorResponse()
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, errors.New(errors.ErrApiError, "OAuth2: token endpoint returned no access token")
+	}
+
+	return tokenResp.token(), nil
+}
@@ -0,0 +1,272 @@
+/*
+authcode_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newAuthCodeOAuth returns an OAuth2 for the authorization code flow using the given token endpoint.
+func newAuthCodeOAuth(tokenURL string, style AuthStyle, secret string) *OAuth2 {
+	cred := &oauth.Credentials{ConsumerKey: "client", ConsumerSecret: secret}
+	return NewOAuth(context.Background(), cred, nil, client.NewHttpClient()).
+		Endpoint(Endpoint{AuthURL: "https://example.com/authorize", TokenURL: tokenURL, AuthStyle: style}).
+		RedirectURL("https://app.example.com/callback")
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkce.Verifier) != 43 {
+		t.Errorf("verifier length = %d, want 43", len(pkce.Verifier))
+	}
+
+	tests := []struct {
+		name    string
+		authURL string
+		pkce    *PKCE
+	}{
+		{"with pkce", "https://example.com/authorize", pkce},
+		{"without pkce", "https://example.com/authorize", nil},
+		{"existing query", "https://example.com/authorize?prompt=consent", pkce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAuthCodeOAuth("", AuthStyleInParams, "secret")
+			a = a.Endpoint(Endpoint{AuthURL: tt.authURL})
+
+			raw := a.AuthCodeURL("xyz", []string{"read", "write"}, tt.pkce, SetAuthURLParam("access_type", "offline"))
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Scheme+"://"+u.Host+u.Path != "https://example.com/authorize" {
+				t.Errorf("url = %s, want the authorization endpoint", raw)
+			}
+
+			q := u.Query()
+			want := map[string]string{
+				"response_type": "code",
+				"client_id":     "client",
+				"redirect_uri":  "https://app.example.com/callback",
+				"scope":         "read write",
+				"state":         "xyz",
+				"access_type":   "offline",
+			}
+			if tt.pkce != nil {
+				want["code_challenge"] = S256Challenge(tt.pkce.Verifier)
+				want["code_challenge_method"] = PKCEMethodS256
+			}
+			if tt.authURL != "https://example.com/authorize" {
+				want["prompt"] = "consent"
+			}
+			for k, v := range want {
+				if got := q.Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+			if len(q) != len(want) {
+				t.Errorf("got parameters %v, want %v", q, want)
+			}
+		})
+	}
+}
+
+// TestS256Challenge uses the example of https://datatracker.ietf.org/doc/html/rfc7636#appendix-B
+func TestS256Challenge(t *testing.T) {
+	got := S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("S256Challenge = %q, want %q", got, want)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  AuthStyle
+		secret string
+		basic  bool
+	}{
+		{"credentials in params", AuthStyleInParams, "secret", false},
+		{"credentials in header", AuthStyleInHeader, "secret", true},
+		{"public client", AuthStyleInHeader, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Error(err)
+				}
+				want := map[string]string{
+					"grant_type":    "authorization_code",
+					"code":          "auth-code",
+					"redirect_uri":  "https://app.example.com/callback",
+					"code_verifier": "verifier",
+				}
+				if !tt.basic {
+					want["client_id"] = "client"
+					want["client_secret"] = tt.secret
+				}
+				for k, v := range want {
+					if got := r.PostForm.Get(k); got != v {
+						t.Errorf("%s = %q, want %q", k, got, v)
+					}
+				}
+
+				user, pass, ok := r.BasicAuth()
+				if ok != tt.basic || (tt.basic && (user != "client" || pass != "secret")) {
+					t.Errorf("basic auth = %q/%q/%v, want %v", user, pass, ok, tt.basic)
+				}
+				if tt.basic && r.PostForm.Has("client_secret") {
+					t.Error("client secret sent in the params and the header")
+				}
+				if r.Header.Get("Accept") != "application/json" {
+					t.Errorf("Accept = %q, want application/json", r.Header.Get("Accept"))
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"access_token":"access","token_type":"Bearer","refresh_token":"refresh","expires_in":3600,"scope":"read write"}`))
+			}))
+			defer server.Close()
+
+			a := newAuthCodeOAuth(server.URL, tt.style, tt.secret)
+			var notified *Token
+			a.OnTokenRefresh(func(token *Token) {
+				notified = token
+			})
+
+			token, err := a.Exchange(context.Background(), "auth-code", "verifier")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.Token != "access" || token.RefreshToken != "refresh" || token.TokenType != "Bearer" {
+				t.Errorf("token = %+v", token)
+			}
+			if d := time.Until(token.Expiry); d < 59*time.Minute || d > time.Hour {
+				t.Errorf("expiry in %v, want about 1h", d)
+			}
+			if a.Token() != token || notified != token {
+				t.Error("exchanged token was not set with UpdateToken")
+			}
+		})
+	}
+}
+
+func TestExchangeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    string
+		body      string
+		kind      error
+		retryable bool
+	}{
+		{"invalid grant", http.StatusBadRequest, "application/json", `{"error":"invalid_grant","error_description":"code expired"}`, socialErrors.ErrBadAuthenticationData, false},
+		{"invalid request", http.StatusBadRequest, "application/json", `{"error":"invalid_request"}`, socialErrors.ErrBadRequest, false},
+		{"error with 200", http.StatusOK, "application/json", `{"error":"bad_verification_code","error_description":"The code passed is incorrect or expired."}`, socialErrors.ErrBadAuthenticationData, false},
+		{"html error page", http.StatusInternalServerError, "text/html", `<html>Internal Server Error</html>`, socialErrors.ErrApiError, true},
+		{"no access token", http.StatusOK, "application/json", `{"token_type":"Bearer"}`, socialErrors.ErrApiError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.header)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			a := newAuthCodeOAuth(server.URL, AuthStyleInParams, "secret")
+			token, err := a.Exchange(context.Background(), "auth-code", "")
+			if token != nil || err == nil {
+				t.Fatalf("Exchange = %+v, %v, want an error", token, err)
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("error = %v, want %v", err, tt.kind)
+			}
+			if socialErrors.IsRetryable(err) != tt.retryable {
+				t.Errorf("retryable = %v, want %v", !tt.retryable, tt.retryable)
+			}
+			if a.Token() != nil {
+				t.Errorf("token = %+v, want none after a failed exchange", a.Token())
+			}
+		})
+	}
+
+	t.Run("missing token endpoint", func(t *testing.T) {
+		a := newAuthCodeOAuth("", AuthStyleInParams, "secret")
+		if _, err := a.Exchange(context.Background(), "auth-code", ""); !errors.Is(err, socialErrors.ErrBadRequest) {
+			t.Errorf("error = %v, want %v", err, socialErrors.ErrBadRequest)
+		}
+	})
+}
+
+func TestExchangeRedirect(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "auth-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	state, err := NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _ := NewState(); other == state {
+		t.Error("NewState returned the same state twice")
+	}
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		kind     error
+		requests int32
+	}{
+		{"state mismatch", url.Values{"state": {"forged"}, "code": {"auth-code"}}, socialErrors.ErrBadRequest, 0},
+		{"missing state", url.Values{"code": {"auth-code"}}, socialErrors.ErrBadRequest, 0},
+		{"access denied", url.Values{"state": {state}, "error": {"access_denied"}, "error_description": {"The user denied the request"}}, socialErrors.ErrForbidden, 0},
+		{"missing code", url.Values{"state": {state}}, socialErrors.ErrBadRequest, 0},
+		{"success", url.Values{"state": {state}, "code": {"auth-code"}}, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+			a := newAuthCodeOAuth(server.URL, AuthStyleInParams, "secret")
+
+			token, err := a.ExchangeRedirect(context.Background(), tt.query, state, "")
+			if tt.kind == nil {
+				if err != nil || token == nil || token.Token != "access" {
+					t.Errorf("ExchangeRedirect = %+v, %v, want the token", token, err)
+				}
+			} else if !errors.Is(err, tt.kind) {
+				t.Errorf("error = %v, want %v", err, tt.kind)
+			}
+			if n := atomic.LoadInt32(&requests); n != tt.requests {
+				t.Errorf("sent %d requests to the token endpoint, want %d", n, tt.requests)
+			}
+		})
+	}
+}
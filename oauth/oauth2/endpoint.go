@@ -0,0 +1,73 @@
+/*
+endpoint.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// AuthStyle represents how the client credentials are sent to the token endpoint.
+type AuthStyle int
+
+const (
+	// AuthStyleInParams sends the client_id and client_secret in the POST body.
+	AuthStyleInParams AuthStyle = iota
+	// AuthStyleInHeader sends the client_id and client_secret using HTTP Basic Authorization.
+	AuthStyleInHeader
+)
+
+// Endpoint represents the OAuth2 authorization and token endpoints of a provider.
+type Endpoint struct {
+	// AuthURL is the url the user is redirected to for granting access.
+	AuthURL string
+	// TokenURL is the url for exchanging an authorization code for a token.
+	TokenURL string
+	// AuthStyle defines how the client credentials are sent to the TokenURL.
+	AuthStyle AuthStyle
+}
+
+const (
+	// PKCEMethodS256 is the only code challenge method supported, since the plain method
+	// does not provide any protection if the authorization request is intercepted.
+	PKCEMethodS256 = "S256"
+
+	pkceVerifierLength = 32
+)
+
+// PKCE holds a code verifier and its challenge for the Proof Key for Code Exchange.
+// The Challenge is sent with the authorization request and the Verifier with the code exchange.
+// See https://datatracker.ietf.org/doc/html/rfc7636
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE returns a new PKCE with a random code verifier and its S256 code challenge.
+func NewPKCE() (*PKCE, error) {
+	b := make([]byte, pkceVerifierLength)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	// 32 bytes result in 43 characters, the minimum length required by RFC 7636 4.1
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: S256Challenge(verifier),
+		Method:    PKCEMethodS256,
+	}, nil
+}
+
+// S256Challenge returns the S256 code challenge of the given code verifier.
+// See https://datatracker.ietf.org/doc/html/rfc7636#section-4.2
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+/*
+errors.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"fmt"
+	"github.com/emrearmagan/go-social/models/errors"
+	"time"
+)
+
+// TokenError represents an error response of a token endpoint with its corresponding http StatusCode response
+// https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+type TokenError struct {
+	StatusCode int
+	Errors     TokenErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
+}
+
+// TokenErrorDetail represents the actual error response from the token endpoint
+type TokenErrorDetail struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (e *TokenError) ErrorDetail() interface{} {
+	return &e.Errors
+}
+
+func (e *TokenError) Error() string {
+	if (e.Errors != TokenErrorDetail{}) {
+		return fmt.Sprintf("OAuth2: %d - %v : %v", e.StatusCode, e.Errors.Error, e.Errors.ErrorDescription)
+	}
+	if e.Body != "" {
+		return fmt.Sprintf("OAuth2: %d - %v", e.StatusCode, e.Body)
+	}
+	return ""
+}
+
+// Empty returns true if empty. Otherwise, at least 1 error message/code is
+// present and false is returned.
+func (e *TokenError) Empty() bool {
+	return e.Errors == TokenErrorDetail{} && e.Body == ""
+}
+
+func (e *TokenError) Status() int {
+	return e.StatusCode
+}
+
+func (e *TokenError) SetStatus(code int) {
+	e.StatusCode = code
+}
+
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *TokenError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *TokenError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
+func (e *TokenError) ReturnErrorResponse() error {
+	switch e.Errors.Error {
+	case "invalid_grant", "bad_verification_code", "incorrect_client_credentials", "invalid_client", "unauthorized_client":
+		return e.socialError(errors.ErrBadAuthenticationData)
+	case "invalid_request", "unsupported_grant_type", "invalid_scope", "redirect_uri_mismatch", "unsupported_response_type":
+		return e.socialError(errors.ErrBadRequest)
+	case "access_denied":
+		return e.socialError(errors.ErrForbidden)
+	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// socialError maps the TokenError onto a SocialError of the given kind.
+func (e *TokenError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   "",
+		StatusCode: e.StatusCode,
+		Code:       e.Errors.Error,
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
+	}
+}
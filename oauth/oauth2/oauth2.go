@@ -12,9 +12,11 @@ import (
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -23,15 +25,19 @@ type OAuth2 struct {
 	ctx         context.Context
 	credentials *oauth.Credentials
 	client      *client.HttpClient
-	token       *Token
-	signer      Signer
+	// token shared by all copies of the OAuth2
+	tokens *tokenState
+	signer Signer
+	// authorization and token endpoint for the authorization code flow
+	endpoint    Endpoint
+	redirectURL string
 }
 
 func NewOAuth(ctx context.Context, c *oauth.Credentials, token *Token, cl *client.HttpClient) *OAuth2 {
 	return &OAuth2{
 		ctx:         ctx,
 		credentials: c,
-		token:       token,
+		tokens:      newTokenState(token),
 		client:      cl,
 		signer: BearerSigner{
 			ConsumerKey:    c.ConsumerKey,
@@ -40,111 +46,210 @@ func NewOAuth(ctx context.Context, c *oauth.Credentials, token *Token, cl *clien
 	}
 }
 
+// clone returns a shallow copy of the OAuth2 object
+func (a *OAuth2) clone() *OAuth2 {
+	c := *a
+	return &c
+}
+
 // New return a copy of OAuth2 object
 func (a *OAuth2) New() *OAuth2 {
-	return &OAuth2{
-		ctx:         a.ctx,
-		credentials: a.credentials,
-		token:       a.token,
-		client:      a.client,
-		signer:      a.signer,
-	}
+	return a.clone()
 }
 
 // NewClient return a new OAuth2 with a new given client
 func (a *OAuth2) NewClient(client *client.HttpClient) *OAuth2 {
-	return &OAuth2{
-		ctx:         a.ctx,
-		credentials: a.credentials,
-		token:       a.token,
-		client:      client,
-		signer:      a.signer,
-	}
+	c := a.clone()
+	c.client = client
+	return c
 }
 
 // Basic return a new OAuth2 with a basic authentication
 func (a *OAuth2) Basic() *OAuth2 {
-	return &OAuth2{
-		ctx:         a.ctx,
-		credentials: a.credentials,
-		token:       a.token,
-		client:      a.client,
-		signer:      BasicSigner{ConsumerKey: a.credentials.ConsumerKey, ConsumerSecret: a.credentials.ConsumerSecret},
-	}
+	c := a.clone()
+	c.signer = BasicSigner{ConsumerKey: a.credentials.ConsumerKey, ConsumerSecret: a.credentials.ConsumerSecret}
+	return c
 }
 
 // Signer sets a Signer for signing the oauth requests
 func (a *OAuth2) Signer(s Signer) *OAuth2 {
-	return &OAuth2{
-		ctx:         a.ctx,
-		credentials: a.credentials,
-		token:       a.token,
-		client:      a.client,
-		signer:      s,
+	c := a.clone()
+	c.signer = s
+	return c
+}
+
+// WithContext return a new OAuth2 sending the requests with the given context, e.g. a request scoped context
+// carrying a deadline. A nil context keeps the current context.
+func (a *OAuth2) WithContext(ctx context.Context) *OAuth2 {
+	c := a.clone()
+	if ctx != nil {
+		c.ctx = ctx
 	}
+	return c
 }
 
+// Context returns the context the requests are sent with. Defaults to context.Background.
+func (a *OAuth2) Context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// Endpoint return a new OAuth2 with the given Endpoint used for the authorization code flow
+func (a *OAuth2) Endpoint(e Endpoint) *OAuth2 {
+	c := a.clone()
+	c.endpoint = e
+	return c
+}
+
+// RedirectURL return a new OAuth2 with the given redirect url used for the authorization code flow.
+// The url must match the one registered for the application.
+func (a *OAuth2) RedirectURL(redirectURL string) *OAuth2 {
+	c := a.clone()
+	c.redirectURL = redirectURL
+	return c
+}
+
+// Get sends a GET request to the given path.
 func (a *OAuth2) Get(path string, resp interface{}, apiError social.ApiErrors, params interface{}) error {
-	cl := a.client.AddQuery(params).Get(path)
+	return a.send(a.client.AddQuery(params).Get(path), resp, apiError)
+}
+
+// Post sends a POST request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil.
+func (a *OAuth2) Post(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Post(path).BodyProvider(body), resp, apiError)
+}
+
+// Put sends a PUT request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil.
+func (a *OAuth2) Put(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Put(path).BodyProvider(body), resp, apiError)
+}
+
+// Patch sends a PATCH request with the given body to the given path.
+// The body is usually a client.JSONBody or client.FormBody and may be nil.
+func (a *OAuth2) Patch(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Patch(path).BodyProvider(body), resp, apiError)
+}
+
+// Delete sends a DELETE request with the given body to the given path. The body may be nil.
+func (a *OAuth2) Delete(path string, body client.BodyProvider, resp interface{}, apiError social.ApiErrors, params interface{}) error {
+	return a.send(a.client.AddQuery(params).Delete(path).BodyProvider(body), resp, apiError)
+}
+
+// send sends the request of the given client. If a TokenSource is set, a token about to expire is refreshed
+// before sending the request. If the token is rejected anyway, it is refreshed once and the request is
+// replayed with the new token.
+func (a *OAuth2) send(cl *client.HttpClient, resp interface{}, apiError social.ApiErrors) error {
+	token := a.Token()
+	if a.tokens.canRefresh(token) && token.ExpiresWithin(expiryDelta) {
+		// If refreshing fails, the current token is still tried. A new token which could not be saved is used anyway
+		if t, _ := a.tokens.refresh(a.Context(), token); t != nil {
+			token = t
+		}
+	}
+
+	httpResp, err := a.do(cl, token, resp, apiError)
+	if !a.tokens.canRefresh(token) || !tokenRejected(httpResp, err) {
+		return err
+	}
+
+	token, _ = a.tokens.refresh(a.Context(), token)
+	if token == nil {
+		// The original error is more meaningful to the caller
+		return err
+	}
+	resetError(apiError)
+	_, err = a.do(cl, token, resp, apiError)
+	return err
+}
+
+// do signs the request of the given client with the token and sends it.
+func (a *OAuth2) do(cl *client.HttpClient, token *Token, resp interface{}, apiError social.ApiErrors) (*http.Response, error) {
+	if token == nil {
+		return nil, errors.New(errors.ErrBadAuthenticationData, "OAuth2: provide valid token")
+	}
+
 	req, err := cl.Request()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req = req.WithContext(a.ctx)
-	for k, v := range a.signer.OAuthParams(a.token.Token) {
+	req = req.WithContext(a.Context())
+	for k, v := range a.signer.OAuthParams(token.Token) {
+		// The Content-Type of a request body is kept, e.g. of a client.FormBody
+		if k == ContentTypeHeaderName && req.Header.Get(k) != "" {
+			continue
+		}
 		req.Header.Set(k, v)
 	}
 
 	httpResp, err := cl.Do(req, resp, apiError.ErrorDetail())
-	if httpResp != nil {
-		if code := httpResp.StatusCode; code >= 300 {
-			apiError.SetStatus(httpResp.StatusCode)
-		}
+	social.SetResponse(apiError, httpResp)
+
+	err = social.RelevantError(err, apiError)
+	cl.RecordError(req.Context(), social.CheckError(err))
+	return httpResp, err
+}
+
+// tokenRejected returns true if the API rejected the token as invalid or expired.
+func tokenRejected(resp *http.Response, err error) bool {
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		return true
 	}
+	if e, ok := social.CheckError(err).(errors.SocialError); ok {
+		return e.Errors == errors.ErrInvalidOrExpiredToken
+	}
+	return false
+}
 
-	return social.RelevantError(err, apiError)
+// resetError resets the decoded error, so it can be reused for replaying a request.
+func resetError(apiError social.ApiErrors) {
+	if v := reflect.ValueOf(apiError.ErrorDetail()); v.Kind() == reflect.Ptr && !v.IsNil() {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+	if r, ok := apiError.(social.RawBodyReceiver); ok {
+		r.SetRawBody(nil)
+	}
+	if r, ok := apiError.(social.RetryAfterReceiver); ok {
+		r.SetRetryAfter(0)
+	}
+	apiError.SetStatus(0)
 }
 
 func (a *OAuth2) RefreshToken(refreshBase string, path string, resp interface{}, apiError social.ApiErrors) error {
-	// Using a new http cl, so we don't mess up the base path for other requests.
+	// The client is copied, so the base path of other requests is left untouched.
 	// Since some APIs use a different base path for refreshing tokens, like reddit
-	cl := a.client.New().Base(refreshBase).Post(path)
+	cl := a.client.Base(refreshBase).Post(path)
 	req, err := cl.Request()
 	if err != nil {
 		return err
 	}
-	req = req.WithContext(a.ctx)
+	req = req.WithContext(a.Context())
 	req, err = a.signRequest(req)
 	if err != nil {
 		return err
 	}
 
 	httpResp, err := cl.Do(req, resp, apiError.ErrorDetail())
-	if httpResp != nil {
-		if code := httpResp.StatusCode; code >= 300 {
-			apiError.SetStatus(httpResp.StatusCode)
-		}
-	}
+	social.SetResponse(apiError, httpResp)
 
 	return social.RelevantError(err, apiError)
 }
 
 func (a *OAuth2) RevokeToken(revokeBase string, path string, resp interface{}, apiError social.ApiErrors) error {
-	cl := a.client.New().Base(revokeBase).Post(path)
+	cl := a.client.Base(revokeBase).Post(path)
 	req, err := cl.Request()
 	if err != nil {
 		return err
 	}
 
-	req = req.WithContext(a.ctx)
+	req = req.WithContext(a.Context())
 
 	httpResp, err := cl.Do(req, resp, apiError.ErrorDetail())
-	if httpResp != nil {
-		if code := httpResp.StatusCode; code >= 300 {
-			apiError.SetStatus(httpResp.StatusCode)
-		}
-	}
+	social.SetResponse(apiError, httpResp)
 	return social.RelevantError(err, apiError)
 }
 
@@ -155,9 +260,12 @@ func (a *OAuth2) signRequest(req *http.Request) (*http.Request, error) {
 
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", a.token.RefreshToken)
+	data.Set("refresh_token", a.Token().RefreshToken)
 
 	req.Body = ioutil.NopCloser(strings.NewReader(data.Encode()))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(data.Encode())), nil
+	}
 
 	for k, v := range a.signer.AuthSigningParams() {
 		req.Header.Set(k, v)
@@ -176,12 +284,74 @@ func (a *OAuth2) Client() *client.HttpClient {
 	return a.client
 }
 
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (a *OAuth2) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return a.client.RateLimit(endpoint)
+}
+
 func (a *OAuth2) Token() *Token {
-	return a.token
+	return a.tokens.get()
+}
+
+// UpdateToken sets the token for all copies of the OAuth2, saves it into the TokenStore, if any, and calls
+// the OnTokenRefresh callback. The token is set even if saving fails.
+func (a *OAuth2) UpdateToken(token *Token) error {
+	return a.tokens.set(token)
+}
+
+// SetTokenStore sets the TokenStore updated tokens are saved into, e.g. after a refresh or an Exchange.
+// The tokens are keyed by the given provider and account. It is shared by all copies of the OAuth2.
+func (a *OAuth2) SetTokenStore(store oauth.TokenStore, provider, account string) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	a.tokens.store = store
+	a.tokens.provider = provider
+	a.tokens.account = account
+}
+
+// SetTokenSource sets the TokenSource used for obtaining a new token once the API rejects the current one.
+// It is shared by all copies of the OAuth2.
+func (a *OAuth2) SetTokenSource(source TokenSource) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	a.tokens.source = source
+	a.tokens.needsRefreshToken = false
 }
 
-func (a *OAuth2) UpdateToken(token *Token) {
-	a.token = token
+// SetRefreshTokenSource is like SetTokenSource, but for a TokenSource which refreshes the token with its
+// refresh token. Requests with a token without a refresh token are not refreshed automatically, since
+// the refresh would fail anyway.
+func (a *OAuth2) SetRefreshTokenSource(source TokenSource) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	a.tokens.source = source
+	a.tokens.needsRefreshToken = true
+}
+
+// OnTokenRefresh sets a callback which is called with the new token whenever it is updated, i.e. after a refresh
+// by the TokenSource, an UpdateToken or an Exchange, e.g. for persisting the token. It is shared by all copies of the OAuth2.
+func (a *OAuth2) OnTokenRefresh(fn func(token *Token)) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	a.tokens.onRefresh = fn
+}
+
+// Refresh obtains a new token from the TokenSource. Concurrent calls result in a single refresh.
+// If the new token could not be saved into the TokenStore, it is returned together with the error.
+func (a *OAuth2) Refresh() (*Token, error) {
+	return a.RefreshContext(a.Context())
+}
+
+// RefreshContext is like Refresh, but passes the given context to the TokenSource.
+func (a *OAuth2) RefreshContext(ctx context.Context) (*Token, error) {
+	if !a.tokens.hasSource() {
+		return nil, errors.New(errors.ErrBadRequest, "OAuth2: no token source set")
+	}
+	return a.tokens.refresh(ctx, a.Token())
 }
 
 func (a *OAuth2) Credentials() oauth.Credentials {
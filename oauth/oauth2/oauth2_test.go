@@ -0,0 +1,142 @@
+/*
+oauth2_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+type searchParams struct {
+	Query string `url:"q"`
+}
+
+// TestRequestMethods sends a request with every method and body type and checks that the server receives
+// the method, the query, the body with its Content-Type and the bearer token. Requests without a body are
+// sent as JSON. Requests with a rejected token are replayed with the full body after the refresh.
+func TestRequestMethods(t *testing.T) {
+	var got struct {
+		method, query, contentType, body string
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get(AuthorizationHeaderName) != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"invalid token"}`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		got.method, got.query, got.contentType, got.body = r.Method, r.URL.RawQuery, r.Header.Get(client.ContentTypeHeader), string(body)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	form := client.FormBody(url.Values{"status": {"hello world"}})
+	json := client.JSONBody(map[string]string{"status": "hello world"})
+	tests := []struct {
+		name        string
+		token       string
+		send        func(a *OAuth2, resp interface{}) error
+		method      string
+		query       string
+		contentType string
+		body        string
+	}{
+		{"get", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Get("/statuses", resp, new(testAPIError), searchParams{Query: "a b"})
+		}, http.MethodGet, "q=a+b", client.ContentTypeJSON, ""},
+		{"post form", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Post("/statuses", form, resp, new(testAPIError), nil)
+		}, http.MethodPost, "", client.ContentTypeForm, "status=hello+world"},
+		{"post json with query", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Post("/statuses", json, resp, new(testAPIError), searchParams{Query: "a b"})
+		}, http.MethodPost, "q=a+b", client.ContentTypeJSON, `{"status":"hello world"}` + "\n"},
+		{"put form", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Put("/statuses/1", form, resp, new(testAPIError), nil)
+		}, http.MethodPut, "", client.ContentTypeForm, "status=hello+world"},
+		{"patch json", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Patch("/statuses/1", json, resp, new(testAPIError), nil)
+		}, http.MethodPatch, "", client.ContentTypeJSON, `{"status":"hello world"}` + "\n"},
+		{"delete", "fresh", func(a *OAuth2, resp interface{}) error {
+			return a.Delete("/statuses/1", nil, resp, new(testAPIError), nil)
+		}, http.MethodDelete, "", client.ContentTypeJSON, ""},
+		{"post form replayed after refresh", "stale", func(a *OAuth2, resp interface{}) error {
+			return a.Post("/statuses", form, resp, new(testAPIError), nil)
+		}, http.MethodPost, "", client.ContentTypeForm, "status=hello+world"},
+		{"patch json replayed after refresh", "stale", func(a *OAuth2, resp interface{}) error {
+			return a.Patch("/statuses/1", json, resp, new(testAPIError), nil)
+		}, http.MethodPatch, "", client.ContentTypeJSON, `{"status":"hello world"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got.method, got.query, got.contentType, got.body = "", "", "", ""
+			a := newTestOAuth(server.URL, NewToken(tt.token, "refresh"))
+			a.SetTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+				return NewToken("fresh", "refresh"), nil
+			}))
+
+			var resp struct {
+				ID string `json:"id"`
+			}
+			if err := tt.send(a, &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ID != "1" {
+				t.Errorf("response id = %q, want 1", resp.ID)
+			}
+			if got.method != tt.method || got.query != tt.query || got.contentType != tt.contentType || got.body != tt.body {
+				t.Errorf("server received %s ?%s %q with Content-Type %q, want %s ?%s %q with %q",
+					got.method, got.query, got.body, got.contentType, tt.method, tt.query, tt.body, tt.contentType)
+			}
+		})
+	}
+}
+
+// TestWithContext checks that the requests are sent with the context of WithContext instead of the one of
+// NewOAuth, which is only the fallback, and that canceling it aborts a request in flight.
+func TestWithContext(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			arrived <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	a := NewOAuth(canceled, cred, NewToken("fresh", ""), client.NewHttpClient().Base(server.URL))
+
+	if err := a.Get("/me", nil, new(testAPIError), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with the context of NewOAuth = %v, want %v", err, context.Canceled)
+	}
+	if err := a.WithContext(context.Background()).Get("/me", nil, new(testAPIError), nil); err != nil {
+		t.Errorf("error with the context of WithContext = %v, want none", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		cancel()
+	}()
+	a = newTestOAuth(server.URL, NewToken("fresh", ""))
+	if err := a.WithContext(ctx).Post("/slow", nil, nil, new(testAPIError), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error of the canceled request = %v, want %v", err, context.Canceled)
+	}
+}
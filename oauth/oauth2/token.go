@@ -6,6 +6,16 @@ Copyright © go-social. All rights reserved.
 
 package oauth2
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// expiryDelta determines how much earlier a token is considered expired than its actual expiration time.
+// It is used to avoid sending a token which expires while the request is in flight.
+const expiryDelta = 10 * time.Second
+
 type OAuthRefreshResponse struct {
 	Token     Token
 	TokenType string   `json:"token_type"`
@@ -13,10 +23,17 @@ type OAuthRefreshResponse struct {
 	Scope     []string `json:"scope"`
 }
 
-// Token represents an OAuth1 AccessToken (Token credentials) and secret
+// Token represents an OAuth2 AccessToken and RefreshToken
 type Token struct {
 	Token        string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	// TokenType is the type of the token, usually "bearer"
+	TokenType string `json:"token_type,omitempty"`
+	// Expiry is the expiration time of the access token. A zero Expiry means the token does not expire
+	// and is omitted from the JSON encoding.
+	Expiry time.Time `json:"expiry,omitempty"`
+	// Scopes are the scopes granted to the token
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // NewToken returns a new OAuth1 Token
@@ -26,3 +43,83 @@ func NewToken(token, refreshToken string) *Token {
 		RefreshToken: refreshToken,
 	}
 }
+
+// MarshalJSON encodes the token and omits a zero Expiry, since omitempty has no effect on a time.Time.
+func (t Token) MarshalJSON() ([]byte, error) {
+	// token has the fields but not the methods of Token, so MarshalJSON is not called recursively
+	type token Token
+	var expiry *time.Time
+	if !t.Expiry.IsZero() {
+		expiry = &t.Expiry
+	}
+	return json.Marshal(struct {
+		token
+		Expiry *time.Time `json:"expiry,omitempty"`
+	}{token(t), expiry})
+}
+
+// ExpiresIn returns the expiration time for a token that expires in the given number of seconds.
+// Zero is returned if seconds is not positive, since providers omit expires_in for non-expiring tokens.
+func ExpiresIn(seconds int) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// Valid returns true if the token has an access token which is not expired.
+func (t *Token) Valid() bool {
+	return t != nil && t.Token != "" && !t.ExpiresWithin(expiryDelta)
+}
+
+// ExpiresWithin returns true if the token expires within the given duration.
+// Tokens without an Expiry never expire.
+func (t *Token) ExpiresWithin(d time.Duration) bool {
+	if t == nil || t.Expiry.IsZero() {
+		return false
+	}
+	return time.Until(t.Expiry) < d
+}
+
+// tokenResponse represents the response of a token endpoint.
+// See https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
+type tokenResponse struct {
+	AccessToken      string     `json:"access_token"`
+	RefreshToken     string     `json:"refresh_token"`
+	TokenType        string     `json:"token_type"`
+	ExpiresIn        int        `json:"expires_in"`
+	Scope            scopeValue `json:"scope"`
+	Error            string     `json:"error"`
+	ErrorDescription string     `json:"error_description"`
+}
+
+func (t *tokenResponse) token() *Token {
+	return &Token{
+		Token:        t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		TokenType:    t.TokenType,
+		Expiry:       ExpiresIn(t.ExpiresIn),
+		Scopes:       t.Scope,
+	}
+}
+
+// scopeValue decodes the granted scopes. Most providers respond with a space separated string,
+// GitHub with a comma separated string and Twitch with an array.
+type scopeValue []string
+
+func (s *scopeValue) UnmarshalJSON(data []byte) error {
+	var scopes []string
+	if err := json.Unmarshal(data, &scopes); err == nil {
+		*s = scopes
+		return nil
+	}
+
+	var scope string
+	if err := json.Unmarshal(data, &scope); err != nil {
+		return err
+	}
+	*s = strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+tokensource.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"sync"
+)
+
+// TokenSource returns a new valid token, e.g. by refreshing the current token.
+// The context is the one of the request which triggered the refresh.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same token.
+func StaticTokenSource(token *Token) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		return token, nil
+	})
+}
+
+// tokenState holds the token shared by an OAuth2 object and all of its copies, so that
+// a refreshed token is used by every service of a client.
+type tokenState struct {
+	mu    sync.RWMutex
+	token *Token
+
+	// refreshMu ensures only one refresh is in flight at a time
+	refreshMu sync.Mutex
+	source    TokenSource
+	// needsRefreshToken is set if the source refreshes the token with its refresh token
+	needsRefreshToken bool
+	onRefresh         func(token *Token)
+
+	// store persists updated tokens if set
+	store    oauth.TokenStore
+	provider string
+	account  string
+}
+
+func newTokenState(token *Token) *tokenState {
+	return &tokenState{token: token}
+}
+
+func (s *tokenState) get() *Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token
+}
+
+// set replaces the token, saves it into the TokenStore, if any, and notifies the refresh callback.
+// The callback is notified even if saving fails.
+func (s *tokenState) set(token *Token) error {
+	s.mu.Lock()
+	s.token = token
+	store, provider, account, onRefresh := s.store, s.provider, s.account, s.onRefresh
+	s.mu.Unlock()
+
+	if token == nil {
+		return nil
+	}
+	var err error
+	if store != nil {
+		err = store.Save(provider, account, token)
+	}
+	if onRefresh != nil {
+		onRefresh(token)
+	}
+	return err
+}
+
+// refresh obtains a new token from the TokenSource with the given context and sets it.
+// Concurrent refreshes are deduplicated: if the given stale token has already been replaced
+// while waiting for another refresh, the current token is returned without refreshing again.
+// A non-nil token is returned together with an error if the new token could not be saved.
+func (s *tokenState) refresh(ctx context.Context, stale *Token) (*Token, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if current := s.get(); current != stale {
+		return current, nil
+	}
+
+	s.mu.RLock()
+	source := s.source
+	s.mu.RUnlock()
+
+	token, err := source.Token(ctx)
+	if err != nil {
+		// The source may have updated the token before failing, e.g. if saving it failed
+		if current := s.get(); current != stale {
+			return current, err
+		}
+		return nil, err
+	}
+
+	// The source may have updated the token already, e.g. with OAuth2.UpdateToken
+	if s.get() != token {
+		err = s.set(token)
+	}
+	return token, err
+}
+
+// hasSource returns true if a TokenSource is set.
+func (s *tokenState) hasSource() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.source != nil
+}
+
+// canRefresh returns true if the TokenSource can refresh the given token, i.e. a TokenSource is set and
+// the token has a refresh token, unless the TokenSource was set with SetTokenSource.
+func (s *tokenState) canRefresh(token *Token) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.source == nil {
+		return false
+	}
+	return !s.needsRefreshToken || (token != nil && token.RefreshToken != "")
+}
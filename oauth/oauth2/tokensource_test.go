@@ -0,0 +1,251 @@
+/*
+tokensource_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+// testAPIError is a minimal social.ApiErrors for the requests of the tests.
+type testAPIError struct {
+	StatusCode int
+	Errors     struct {
+		Message string `json:"message"`
+	}
+}
+
+func (e *testAPIError) ErrorDetail() interface{}   { return &e.Errors }
+func (e *testAPIError) Error() string              { return e.Errors.Message }
+func (e *testAPIError) Empty() bool                { return e.Errors.Message == "" }
+func (e *testAPIError) Status() int                { return e.StatusCode }
+func (e *testAPIError) SetStatus(code int)         { e.StatusCode = code }
+func (e *testAPIError) ReturnErrorResponse() error { return e }
+
+// tokenServer accepts only the bearer token "fresh" and rejects every other token with 401.
+func tokenServer() *httptest.Server {
+	return httptest.NewServer(tokenHandler())
+}
+
+func tokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get(AuthorizationHeaderName) != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"invalid token"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+}
+
+func newTestOAuth(serverURL string, token *Token) *OAuth2 {
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	return NewOAuth(context.Background(), cred, token, client.NewHttpClient().Base(serverURL))
+}
+
+// TestRefreshUsesRequestContext checks that the TokenSource receives the context of the rejected request
+// and not the one the OAuth2 was created with.
+func TestRefreshUsesRequestContext(t *testing.T) {
+	server := tokenServer()
+	defer server.Close()
+
+	a := newTestOAuth(server.URL, NewToken("stale", "refresh"))
+	var got interface{}
+	a.SetTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		got = ctx.Value(ctxKey{})
+		return NewToken("fresh", "refresh"), nil
+	}))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	if err := a.WithContext(ctx).Get("/me", nil, new(testAPIError), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got != "request" {
+		t.Errorf("TokenSource context value = %v, want the request context", got)
+	}
+	if a.Token().Token != "fresh" {
+		t.Errorf("token = %q, want the refreshed token", a.Token().Token)
+	}
+
+	if _, err := a.RefreshContext(context.WithValue(context.Background(), ctxKey{}, "manual")); err != nil {
+		t.Fatal(err)
+	}
+	if got != "manual" {
+		t.Errorf("TokenSource context value = %v, want the context of RefreshContext", got)
+	}
+}
+
+// TestConcurrentRefresh checks that concurrently rejected requests share a single refresh.
+func TestConcurrentRefresh(t *testing.T) {
+	server := tokenServer()
+	defer server.Close()
+
+	a := newTestOAuth(server.URL, NewToken("stale", "refresh"))
+	var refreshes int32
+	a.SetTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		atomic.AddInt32(&refreshes, 1)
+		return NewToken("fresh", "refresh"), nil
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := a.Get("/me", nil, new(testAPIError), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("got %d refreshes, want 1", n)
+	}
+}
+
+// TestUpdateTokenNotifies checks that a token set with UpdateToken, e.g. by a manual RefreshToken
+// of a provider, is saved and passed to the OnTokenRefresh callback exactly once, like a refreshed token.
+func TestUpdateTokenNotifies(t *testing.T) {
+	server := tokenServer()
+	defer server.Close()
+
+	a := newTestOAuth(server.URL, NewToken("stale", "refresh"))
+	store := oauth.NewMemoryStore()
+	a.SetTokenStore(store, "test", "")
+
+	var notified []string
+	a.OnTokenRefresh(func(token *Token) {
+		notified = append(notified, token.Token)
+	})
+
+	// A TokenSource updating the token itself, like the ones of the providers
+	a.SetTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		token := NewToken("fresh", "refresh")
+		return token, a.UpdateToken(token)
+	}))
+
+	if err := a.UpdateToken(NewToken("manual", "refresh")); err != nil {
+		t.Fatal(err)
+	}
+	var saved Token
+	if err := store.Load("test", "", &saved); err != nil || saved.Token != "manual" {
+		t.Fatalf("stored token = %q, %v, want manual", saved.Token, err)
+	}
+
+	if err := a.Get("/me", nil, new(testAPIError), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(notified, ","); got != "manual,fresh" {
+		t.Errorf("notified tokens = %s, want manual,fresh", got)
+	}
+}
+
+// TestRefreshWithoutRefreshToken checks that a rejected token without a refresh token is only refreshed
+// by a TokenSource set with SetTokenSource.
+func TestRefreshWithoutRefreshToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		set       func(a *OAuth2, source TokenSource)
+		refreshes int32
+	}{
+		{"refresh token source", (*OAuth2).SetRefreshTokenSource, 0},
+		{"custom source", (*OAuth2).SetTokenSource, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tokenServer()
+			defer server.Close()
+
+			a := newTestOAuth(server.URL, NewToken("stale", ""))
+			var refreshes int32
+			tt.set(a, TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+				atomic.AddInt32(&refreshes, 1)
+				return NewToken("fresh", ""), nil
+			}))
+
+			err := a.Get("/me", nil, new(testAPIError), nil)
+			if n := atomic.LoadInt32(&refreshes); n != tt.refreshes {
+				t.Errorf("got %d refreshes, want %d", n, tt.refreshes)
+			}
+			if (err == nil) != (tt.refreshes == 1) {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
+
+// failingStore is a TokenStore failing to save.
+type failingStore struct {
+	*oauth.MemoryStore
+}
+
+func (s failingStore) Save(provider, account string, token interface{}) error {
+	return errors.New("disk full")
+}
+
+// TestRefreshSavesToken checks that refreshed tokens are saved into the TokenStore, both if the token is about
+// to expire and if it is rejected. A token which could not be saved is used anyway.
+func TestRefreshSavesToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    *Token
+		store    oauth.TokenStore
+		saved    bool
+		requests int32
+	}{
+		{"rejected token", NewToken("stale", "refresh"), oauth.NewMemoryStore(), true, 2},
+		{"expired token", &Token{Token: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}, oauth.NewMemoryStore(), true, 1},
+		{"rejected token not saved", NewToken("stale", "refresh"), failingStore{oauth.NewMemoryStore()}, false, 2},
+		{"expired token not saved", &Token{Token: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}, failingStore{oauth.NewMemoryStore()}, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			handler := tokenHandler()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				handler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			a := newTestOAuth(server.URL, tt.token)
+			a.SetTokenStore(tt.store, "test", "work")
+			a.SetTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+				return NewToken("fresh", "refresh"), nil
+			}))
+
+			// The store is shared by the copies of the OAuth2
+			if err := a.WithContext(context.Background()).Get("/me", nil, new(testAPIError), nil); err != nil {
+				t.Fatal(err)
+			}
+			if n := atomic.LoadInt32(&requests); n != tt.requests {
+				t.Errorf("sent %d requests, want %d", n, tt.requests)
+			}
+			var saved Token
+			err := tt.store.Load("test", "work", &saved)
+			if tt.saved && (err != nil || saved.Token != "fresh") {
+				t.Errorf("stored token = %q, %v, want fresh", saved.Token, err)
+			}
+			if a.Token().Token != "fresh" {
+				t.Errorf("token = %q, want fresh", a.Token().Token)
+			}
+		})
+	}
+}
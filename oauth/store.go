@@ -0,0 +1,85 @@
+/*
+store.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emrearmagan/go-social/models/errors"
+	"sync"
+)
+
+// TokenStore persists the tokens of a provider, so that rotated tokens are not lost on restart.
+// Tokens are keyed by the provider, e.g. "spotify", and an account name, which is empty for a single account.
+type TokenStore interface {
+	// Load decodes the stored token into the value pointed to by token.
+	// Returns an ErrNotFound SocialError if no token is stored.
+	Load(provider, account string, token interface{}) error
+	// Save stores the given token, replacing a previously stored one.
+	Save(provider, account string, token interface{}) error
+	// Delete removes the stored token. Deleting a token which is not stored is not an error.
+	Delete(provider, account string) error
+}
+
+// StoreKey returns the key under which a token of the provider and account is stored.
+func StoreKey(provider, account string) string {
+	if account == "" {
+		return provider
+	}
+	return provider + "/" + account
+}
+
+// ErrTokenNotFound returns the error a TokenStore returns if no token is stored for the provider and account.
+func ErrTokenNotFound(provider, account string) error {
+	return errors.New(errors.ErrNotFound, fmt.Sprintf("TokenStore: no token stored for %s", StoreKey(provider, account)))
+}
+
+// MemoryStore is a TokenStore keeping the tokens in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	tokens map[string][]byte
+}
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string][]byte)}
+}
+
+// Load decodes the stored token into the value pointed to by token.
+func (s *MemoryStore) Load(provider, account string, token interface{}) error {
+	s.mu.RLock()
+	data, ok := s.tokens[StoreKey(provider, account)]
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrTokenNotFound(provider, account)
+	}
+	return json.Unmarshal(data, token)
+}
+
+// Save stores the given token. The token is encoded, so later changes to it are not reflected in the store.
+func (s *MemoryStore) Save(provider, account string, token interface{}) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[StoreKey(provider, account)] = data
+	return nil
+}
+
+// Delete removes the stored token.
+func (s *MemoryStore) Delete(provider, account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, StoreKey(provider, account))
+	return nil
+}
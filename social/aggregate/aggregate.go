@@ -0,0 +1,218 @@
+/*
+aggregate.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+// Package aggregate fetches the users of multiple providers concurrently.
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emrearmagan/go-social/config"
+	"github.com/emrearmagan/go-social/models"
+	"github.com/emrearmagan/go-social/social"
+	"github.com/emrearmagan/go-social/social/client"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultParallelism is the default number of providers fetched at the same time.
+	DefaultParallelism = 4
+	// DefaultTimeout is the default timeout of a single provider, including retries.
+	DefaultTimeout = 30 * time.Second
+)
+
+// Aggregator fetches the users of multiple providers concurrently.
+type Aggregator struct {
+	providers []social.Provider
+	// failed are the results of the accounts whose provider could not be built
+	failed []Result
+
+	parallelism   int
+	timeout       time.Duration
+	clientOptions []client.Option
+}
+
+// Option configures an Aggregator.
+type Option func(*Aggregator)
+
+// WithParallelism limits the number of providers fetched at the same time. Values lower than 1 are ignored.
+func WithParallelism(n int) Option {
+	return func(a *Aggregator) {
+		if n > 0 {
+			a.parallelism = n
+		}
+	}
+}
+
+// WithTimeout sets the timeout of every provider. Providers exceeding it fail with context.DeadlineExceeded.
+// The timeout applies to each provider call on its own and starts once the provider is fetched, so waiting
+// for a free slot of WithParallelism does not count. Values lower than 1 disable the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *Aggregator) {
+		a.timeout = timeout
+	}
+}
+
+// WithClientOptions configures the http clients of the providers built by FromConfig,
+// e.g. for setting a logger or retries for all providers.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(a *Aggregator) {
+		a.clientOptions = append(a.clientOptions, opts...)
+	}
+}
+
+// New returns an Aggregator for the given providers.
+func New(providers []social.Provider, opts ...Option) *Aggregator {
+	a := &Aggregator{
+		providers:   providers,
+		parallelism: DefaultParallelism,
+		timeout:     DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// FromConfig returns an Aggregator for all accounts of the config. The providers are built by their name
+// with social.New, so the provider packages must be imported. Accounts whose provider cannot be built
+// are reported as failed by every Fetch.
+func FromConfig(ctx context.Context, cfg *config.Config, opts ...Option) *Aggregator {
+	a := New(nil, opts...)
+
+	accounts := cfg.Accounts()
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		account := accounts[name]
+		account.Options = append(append([]client.Option{}, a.clientOptions...), account.Options...)
+
+		provider, err := social.New(ctx, name, account)
+		if err != nil {
+			a.failed = append(a.failed, Result{Provider: name, Err: err})
+			continue
+		}
+		a.providers = append(a.providers, provider)
+	}
+	return a
+}
+
+// Providers returns the providers of the Aggregator.
+func (a *Aggregator) Providers() []social.Provider {
+	return a.providers
+}
+
+// Result is the result of a single provider.
+type Result struct {
+	// Provider is the name of the provider
+	Provider string
+	// User is nil if the provider failed
+	User *models.SocialUser
+	Err  error
+	// Duration is the time it took to fetch the user
+	Duration time.Duration
+}
+
+// ProviderError is the error of a single provider. The error of the provider can be retrieved
+// with errors.Is and errors.As, e.g. errors.Is(err, errors.ErrRateLimit).
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Results are the results of all providers, sorted by the provider name.
+type Results []Result
+
+// Users returns the users of the succeeded providers.
+func (r Results) Users() []*models.SocialUser {
+	var users []*models.SocialUser
+	for _, result := range r {
+		if result.User != nil {
+			users = append(users, result.User)
+		}
+	}
+	return users
+}
+
+// Err returns the errors of the failed providers joined as ProviderErrors or nil if all succeeded.
+func (r Results) Err() error {
+	var errs []error
+	for _, result := range r {
+		if result.Err != nil {
+			errs = append(errs, &ProviderError{Provider: result.Provider, Err: result.Err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Fetch fetches the users of all providers concurrently. Failing providers do not affect the others,
+// so the Results contain the users of all succeeded providers and the errors of the failed ones.
+// The returned error is Results.Err.
+func (a *Aggregator) Fetch(ctx context.Context) (Results, error) {
+	results := make(Results, len(a.providers), len(a.providers)+len(a.failed))
+	sem := make(chan struct{}, a.parallelism)
+
+	var wg sync.WaitGroup
+	for i, provider := range a.providers {
+		wg.Add(1)
+		go func(i int, provider social.Provider) {
+			defer wg.Done()
+
+			results[i].Provider = provider.Name()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			results[i] = a.fetch(ctx, provider)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	results = append(results, a.failed...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Provider < results[j].Provider
+	})
+	return results, results.Err()
+}
+
+// fetch fetches the user of the given provider with the timeout of the Aggregator.
+func (a *Aggregator) fetch(ctx context.Context, provider social.Provider) Result {
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	user, err := provider.GoSocialUserContext(ctx)
+	result := Result{
+		Provider: provider.Name(),
+		Err:      err,
+		Duration: time.Since(start),
+	}
+	if err == nil {
+		result.User = user
+	}
+	return result
+}
@@ -0,0 +1,219 @@
+/*
+aggregate_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/config"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social"
+	_ "github.com/emrearmagan/go-social/social/github"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProvider returns its user after the given delay or fails with err.
+// A negative delay blocks until the context is done.
+type fakeProvider struct {
+	name  string
+	delay time.Duration
+	user  *models.SocialUser
+	err   error
+
+	// running and maxRunning count the concurrent calls of all fakeProviders sharing them
+	running, maxRunning *int32
+}
+
+func (p *fakeProvider) Name() string                      { return p.name }
+func (p *fakeProvider) Capabilities() social.Capabilities { return 0 }
+func (p *fakeProvider) GoSocialUser() (*models.SocialUser, error) {
+	return p.GoSocialUserContext(context.Background())
+}
+func (p *fakeProvider) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	if p.running != nil {
+		n := atomic.AddInt32(p.running, 1)
+		defer atomic.AddInt32(p.running, -1)
+		for {
+			max := atomic.LoadInt32(p.maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(p.maxRunning, max, n) {
+				break
+			}
+		}
+	}
+
+	wait := make(<-chan time.Time)
+	if p.delay >= 0 {
+		wait = time.After(p.delay)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-wait:
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.user != nil {
+		return p.user, nil
+	}
+	return &models.SocialUser{Provider: p.name, Username: p.name}, nil
+}
+func (p *fakeProvider) FollowersIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+func (p *fakeProvider) FollowingIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+func (p *fakeProvider) RefreshContext(ctx context.Context) error { return social.ErrUnsupported }
+func (p *fakeProvider) RevokeContext(ctx context.Context) error  { return social.ErrUnsupported }
+
+func TestFetchParallelism(t *testing.T) {
+	var running, maxRunning int32
+	var providers []social.Provider
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		providers = append(providers, &fakeProvider{name: name, delay: 20 * time.Millisecond, running: &running, maxRunning: &maxRunning})
+	}
+
+	results, err := New(providers, WithParallelism(2)).Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Users()) != 6 {
+		t.Errorf("got %d users, want 6", len(results.Users()))
+	}
+	if maxRunning != 2 {
+		t.Errorf("fetched %d providers at the same time, want 2", maxRunning)
+	}
+}
+
+// TestFetchTimeout checks that the timeout applies to every provider on its own, so providers waiting
+// for a free slot do not time out, and that a hanging provider does not affect the others.
+func TestFetchTimeout(t *testing.T) {
+	providers := []social.Provider{
+		&fakeProvider{name: "a", delay: 30 * time.Millisecond},
+		&fakeProvider{name: "b", delay: 30 * time.Millisecond},
+		&fakeProvider{name: "c", delay: -1},
+		&fakeProvider{name: "d", delay: 30 * time.Millisecond},
+	}
+
+	results, err := New(providers, WithParallelism(1), WithTimeout(60*time.Millisecond)).Fetch(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	for _, result := range results {
+		if hanging := result.Provider == "c"; hanging != (result.Err != nil) {
+			t.Errorf("%s: error = %v", result.Provider, result.Err)
+		}
+	}
+	if len(results.Users()) != 3 {
+		t.Errorf("got %d users, want 3", len(results.Users()))
+	}
+}
+
+func TestFetchPartialResults(t *testing.T) {
+	providers := []social.Provider{
+		&fakeProvider{name: "twitter", err: socialErrors.New(socialErrors.ErrRateLimit, "rate limit exceeded")},
+		&fakeProvider{name: "github"},
+		&fakeProvider{name: "spotify"},
+	}
+
+	results, err := New(providers).Fetch(context.Background())
+	if !errors.Is(err, socialErrors.ErrRateLimit) {
+		t.Fatalf("error = %v, want %v", err, socialErrors.ErrRateLimit)
+	}
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Provider != "twitter" {
+		t.Errorf("ProviderError = %+v, want twitter", providerErr)
+	}
+	if err != nil && results.Err().Error() != err.Error() {
+		t.Errorf("Results.Err() = %v, want the returned error", results.Err())
+	}
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Provider)
+	}
+	if want := "github,spotify,twitter"; strings.Join(names, ",") != want {
+		t.Errorf("results sorted as %v, want %s", names, want)
+	}
+	if len(results.Users()) != 2 {
+		t.Errorf("got %d users, want 2", len(results.Users()))
+	}
+
+	if err := (Results{{Provider: "github", User: &models.SocialUser{}}}).Err(); err != nil {
+		t.Errorf("Err() of succeeded results = %v, want nil", err)
+	}
+}
+
+// TestFromConfig checks that accounts without a registered provider are reported as failed and
+// that the Facebook entry of the config, which has no provider, is skipped.
+func TestFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		Github:   config.OAuth2Config{Token: oauth2.Token{Token: "github"}},
+		Facebook: config.OAuth2Config{Token: oauth2.Token{Token: "facebook"}},
+		Twitch:   config.OAuth2Config{Token: oauth2.Token{Token: "twitch"}},
+	}
+
+	a := FromConfig(context.Background(), cfg)
+	if len(a.Providers()) != 1 || a.Providers()[0].Name() != "github" {
+		t.Errorf("providers = %v, want github", a.Providers())
+	}
+	// The twitch package is not imported
+	if len(a.failed) != 1 || a.failed[0].Provider != "twitch" || !errors.Is(a.failed[0].Err, socialErrors.ErrNotFound) {
+		t.Errorf("failed = %+v, want twitch", a.failed)
+	}
+}
+
+func TestSummaryJSON(t *testing.T) {
+	following := 10
+	results := Results{
+		{Provider: "github", User: &models.SocialUser{Username: "octo", Followers: 5, Following: &following, ContentCount: 7}},
+		{Provider: "spotify", User: &models.SocialUser{Username: "dj", Followers: 3}},
+		{Provider: "twitter", Err: &ProviderError{Provider: "twitter", Err: errors.New("rate limit exceeded")}},
+	}
+
+	content, err := results.Summary().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "total_followers": 8,
+  "total_following": 10,
+  "total_content": 7,
+  "succeeded": 2,
+  "failed": 1,
+  "providers": [
+    {
+      "provider": "github",
+      "username": "octo",
+      "followers": 5,
+      "following": 10,
+      "content_count": 7
+    },
+    {
+      "provider": "spotify",
+      "username": "dj",
+      "followers": 3,
+      "content_count": 0
+    },
+    {
+      "provider": "twitter",
+      "followers": 0,
+      "content_count": 0,
+      "error": "twitter: rate limit exceeded"
+    }
+  ]
+}`
+	if string(content) != want {
+		t.Errorf("JSON() = %s, want %s", content, want)
+	}
+}
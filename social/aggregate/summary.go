@@ -0,0 +1,69 @@
+/*
+summary.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package aggregate
+
+import (
+	"encoding/json"
+)
+
+// Summary combines the users of all providers.
+type Summary struct {
+	// TotalFollowers is the sum of the followers of all succeeded providers
+	TotalFollowers int64 `json:"total_followers"`
+	// TotalFollowing is the sum of the following of all succeeded providers which provide it
+	TotalFollowing int64 `json:"total_following"`
+	// TotalContent is the sum of the content, e.g. tweets, shots or repositories, of all succeeded providers
+	TotalContent int64 `json:"total_content"`
+	Succeeded    int   `json:"succeeded"`
+	Failed       int   `json:"failed"`
+	// Providers is the breakdown per provider, sorted by the provider name
+	Providers []ProviderSummary `json:"providers"`
+}
+
+// ProviderSummary is the summary of a single provider.
+type ProviderSummary struct {
+	Provider     string `json:"provider"`
+	Username     string `json:"username,omitempty"`
+	Followers    int    `json:"followers"`
+	Following    *int   `json:"following,omitempty"` // nil if the provider does not provide it or failed
+	ContentCount int64  `json:"content_count"`
+	// Error is the error message of a failed provider
+	Error string `json:"error,omitempty"`
+}
+
+// Summary returns the combined summary of the results.
+func (r Results) Summary() Summary {
+	summary := Summary{Providers: make([]ProviderSummary, 0, len(r))}
+	for _, result := range r {
+		p := ProviderSummary{Provider: result.Provider}
+		switch {
+		case result.Err != nil:
+			p.Error = result.Err.Error()
+			summary.Failed++
+		case result.User != nil:
+			u := result.User
+			p.Username = u.Username
+			p.Followers = u.Followers
+			p.Following = u.Following
+			p.ContentCount = u.ContentCount
+
+			summary.TotalFollowers += int64(u.Followers)
+			if u.Following != nil {
+				summary.TotalFollowing += int64(*u.Following)
+			}
+			summary.TotalContent += u.ContentCount
+			summary.Succeeded++
+		}
+		summary.Providers = append(summary.Providers, p)
+	}
+	return summary
+}
+
+// JSON returns the indented JSON encoding of the summary.
+func (s Summary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
@@ -6,6 +6,13 @@ Copyright © go-social. All rights reserved.
 
 package social
 
+import (
+	"errors"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"time"
+)
+
 type ApiErrors interface {
 	Error() string
 	ErrorDetail() interface{}
@@ -15,9 +22,47 @@ type ApiErrors interface {
 	SetStatus(code int)
 }
 
+// RawBodyReceiver is implemented by ApiErrors, which keep the raw body of error responses
+// that could not be decoded, e.g. HTML error pages.
+type RawBodyReceiver interface {
+	SetRawBody(body []byte)
+}
+
+// RetryAfterReceiver is implemented by ApiErrors, which keep the wait time requested by the API
+// with the Retry-After or rate limit reset headers.
+type RetryAfterReceiver interface {
+	SetRetryAfter(d time.Duration)
+}
+
+// SetResponse sets the status code of the error response on the apiError. If the apiError implements
+// RetryAfterReceiver, the wait time requested by the API is set as well. Success responses are ignored.
+func SetResponse(apiError ApiErrors, resp *http.Response) {
+	if resp == nil || resp.StatusCode < 300 {
+		return
+	}
+	apiError.SetStatus(resp.StatusCode)
+	if receiver, ok := apiError.(RetryAfterReceiver); ok {
+		if d, ok := client.RetryAfter(resp); ok {
+			receiver.SetRetryAfter(d)
+		}
+	}
+}
+
 // RelevantError returns any non-nil http-related error if any. If the decoded apiError is non-zero
 // the apiError is returned. Otherwise, no errors occurred, returns nil.
+// If the error response could not be decoded, the raw body is kept in the apiError if it implements RawBodyReceiver.
 func RelevantError(httpError error, apiError ApiErrors) error {
+	var raw *client.RawResponseError
+	if errors.As(httpError, &raw) && raw.StatusCode >= 300 {
+		if receiver, ok := apiError.(RawBodyReceiver); ok {
+			receiver.SetRawBody(raw.Body)
+			if apiError.Status() == 0 {
+				apiError.SetStatus(raw.StatusCode)
+			}
+			return apiError
+		}
+	}
+
 	if httpError != nil {
 		return httpError
 	}
@@ -0,0 +1,98 @@
+/*
+body.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/go-querystring/query"
+	"io"
+	"net/url"
+	"strings"
+)
+
+const (
+	// ContentTypeHeader is the header key of the content type
+	ContentTypeHeader = "Content-Type"
+	// ContentTypeJSON is the content type of JSON bodies
+	ContentTypeJSON = "application/json"
+	// ContentTypeForm is the content type of form encoded bodies
+	ContentTypeForm = "application/x-www-form-urlencoded"
+	// ContentTypeXML and ContentTypeTextXML are the content types of XML bodies
+	ContentTypeXML     = "application/xml"
+	ContentTypeTextXML = "text/xml"
+)
+
+// BodyProvider provides the Body and the Content-Type of a request.
+// The Body is requested for every request built by the HttpClient.
+type BodyProvider interface {
+	// ContentType returns the Content-Type of the body. An empty Content-Type is not set.
+	ContentType() string
+	// Body returns the encoded body.
+	Body() (io.Reader, error)
+}
+
+// JSONBody returns a BodyProvider encoding the given value as JSON.
+func JSONBody(v interface{}) BodyProvider {
+	return jsonBodyProvider{payload: v}
+}
+
+// FormBody returns a BodyProvider encoding the given value as form.
+// The value is either url.Values or an url tagged struct.
+func FormBody(v interface{}) BodyProvider {
+	return formBodyProvider{payload: v}
+}
+
+// bodyProvider provides the raw body without a Content-Type.
+type bodyProvider struct {
+	body io.Reader
+}
+
+func (p bodyProvider) ContentType() string {
+	return ""
+}
+
+func (p bodyProvider) Body() (io.Reader, error) {
+	return p.body, nil
+}
+
+// jsonBodyProvider encodes a JSON tagged struct value as the body of a request.
+type jsonBodyProvider struct {
+	payload interface{}
+}
+
+func (p jsonBodyProvider) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (p jsonBodyProvider) Body() (io.Reader, error) {
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(p.payload); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// formBodyProvider encodes url.Values or an url tagged struct value as the body of a request.
+type formBodyProvider struct {
+	payload interface{}
+}
+
+func (p formBodyProvider) ContentType() string {
+	return ContentTypeForm
+}
+
+func (p formBodyProvider) Body() (io.Reader, error) {
+	values, ok := p.payload.(url.Values)
+	if !ok {
+		var err error
+		if values, err = query.Values(p.payload); err != nil {
+			return nil, err
+		}
+	}
+	return strings.NewReader(values.Encode()), nil
+}
@@ -0,0 +1,323 @@
+/*
+cache.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FromCacheHeader is set on responses served from the Cache after the API answered with 304 Not Modified.
+const FromCacheHeader = "X-From-Cache"
+
+// Cache stores the responses of GET requests, so they can be revalidated with
+// If-None-Match and If-Modified-Since. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored for the key, if any.
+	Get(key string) (*CacheEntry, bool)
+	// Set stores the entry for the key.
+	Set(key string, entry *CacheEntry)
+	// Delete removes the entry of the key.
+	Delete(key string)
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+}
+
+// response returns the cached response for the given request.
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(FromCacheHeader, "1")
+	return &http.Response{
+		Status:        http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// WithCache enables conditional requests. GET responses with an ETag or Last-Modified header are stored in the
+// given Cache and revalidated by the following requests. If the API answers with 304 Not Modified, the cached
+// response is decoded instead. A nil Cache disables caching.
+func WithCache(cache Cache) Option {
+	return func(c *HttpClient) {
+		c.cache = cache
+	}
+}
+
+// cacheable returns true if the response of the request may be cached.
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.Header.Get("Range") == ""
+}
+
+// requestCacheKey returns the key of the signed request. The key contains the credentials of the Authorization header,
+// so the responses of different accounts sharing a Cache are kept apart.
+func requestCacheKey(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if strings.HasPrefix(auth, "OAuth ") {
+		// OAuth1 headers contain a nonce and timestamp. Only the consumer key and token identify the account.
+		var identity []string
+		for _, param := range strings.Split(strings.TrimPrefix(auth, "OAuth "), ",") {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "oauth_consumer_key=") || strings.HasPrefix(param, "oauth_token=") {
+				identity = append(identity, param)
+			}
+		}
+		auth = strings.Join(identity, ",")
+	}
+
+	sum := sha256.Sum256([]byte(auth))
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(sum[:])
+}
+
+// revalidateCached returns a Middleware sending GET requests conditionally with the validators of the cached response.
+// See WithCache.
+func (c *HttpClient) revalidateCached(next Doer) Doer {
+	if c.cache == nil {
+		return next
+	}
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if !cacheable(req) {
+			return next.Do(req)
+		}
+		key := requestCacheKey(req)
+		entry := conditional(c.cache, key, req)
+		resp, err := next.Do(req)
+		if resp == nil {
+			return resp, err
+		}
+		if resp, err = revalidate(c.cache, key, entry, req, resp); err != nil {
+			return nil, abortError{err}
+		}
+		return resp, nil
+	})
+}
+
+// conditional returns the cached entry of the request and sets the validators of the entry on the request.
+func conditional(cache Cache, key string, req *http.Request) *CacheEntry {
+	entry, ok := cache.Get(key)
+	if !ok {
+		return nil
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	return entry
+}
+
+// revalidate returns the cached response if the API answered with 304 Not Modified.
+// Successful responses with validators are stored in the cache.
+func revalidate(cache Cache, key string, entry *CacheEntry, req *http.Request, resp *http.Response) (*http.Response, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		// 304 responses carry the updated meta data, e.g. rate limits
+		updated := *entry
+		updated.Header = entry.Header.Clone()
+		for k, v := range resp.Header {
+			updated.Header[k] = v
+		}
+		updated.StoredAt = time.Now()
+		cache.Set(key, &updated)
+		return updated.response(req), nil
+
+	case resp.StatusCode == http.StatusOK:
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if (etag == "" && lastModified == "") || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+			return resp, nil
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		cache.Set(key, &CacheEntry{
+			StatusCode:   resp.StatusCode,
+			Header:       resp.Header.Clone(),
+			Body:         body,
+			ETag:         etag,
+			LastModified: lastModified,
+			StoredAt:     time.Now(),
+		})
+	}
+	return resp, nil
+}
+
+// LRUCache is an in-memory Cache, which evicts the least recently used entries once it is full.
+// It is safe for concurrent use.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns a new LRUCache holding at most capacity entries.
+// A capacity <= 0 is treated as 1.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// DiskCache is a Cache storing every entry as JSON file in a directory, so the cache survives restarts.
+// Failing reads and writes are treated as cache misses.
+// It is safe for concurrent use.
+type DiskCache struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewDiskCache returns a new DiskCache storing the entries in the given directory.
+// The directory is created on the first Set.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+// path returns the file of the key. Keys are hashed, since they contain urls.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	content, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	entry := new(CacheEntry)
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *DiskCache) Set(key string, entry *CacheEntry) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+	// Write into a temporary file first, so a crash never leaves a partially written entry
+	tmp, err := ioutil.TempFile(c.dir, "entry.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(content)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c *DiskCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	os.Remove(c.path(key))
+}
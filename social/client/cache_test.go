@@ -0,0 +1,219 @@
+/*
+cache_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/emrearmagan/go-social/social/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const lastModified = "Sat, 17 Oct 2026 10:00:00 GMT"
+
+// cachingServer answers with an ETag and Last-Modified and with 304 Not Modified to requests carrying both validators.
+// The validators of every request are appended to the given slice.
+func cachingServer(validators *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*validators = append(*validators, r.Header.Get("If-None-Match")+"|"+r.Header.Get("If-Modified-Since"))
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == lastModified {
+			w.Header().Set("X-RateLimit-Remaining", "41")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Write([]byte(`{"name":"go-social"}`))
+	}))
+}
+
+// TestCacheRevalidation checks that a cached response is revalidated with If-None-Match and If-Modified-Since
+// and that its body is decoded on 304 Not Modified, marked with the X-From-Cache header.
+func TestCacheRevalidation(t *testing.T) {
+	var validators []string
+	server := cachingServer(&validators)
+	defer server.Close()
+
+	cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithCache(client.NewLRUCache(8)))
+	for i, wantFromCache := range []bool{false, true} {
+		var captured client.Response
+		req, err := cl.Get("/me").Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = req.WithContext(client.CaptureResponse(context.Background(), &captured))
+
+		var user struct {
+			Name string `json:"name"`
+		}
+		resp, err := cl.Do(req, &user, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || user.Name != "go-social" {
+			t.Errorf("request %d: %d %+v, want the user", i, resp.StatusCode, user)
+		}
+		if fromCache := resp.Header.Get(client.FromCacheHeader) == "1"; fromCache != wantFromCache || captured.FromCache != wantFromCache {
+			t.Errorf("request %d: %s = %q, FromCache = %v, want %v", i, client.FromCacheHeader, resp.Header.Get(client.FromCacheHeader), captured.FromCache, wantFromCache)
+		}
+	}
+
+	want := []string{"|", `"v1"|` + lastModified}
+	if strings.Join(validators, ",") != strings.Join(want, ",") {
+		t.Errorf("validators = %q, want %q", validators, want)
+	}
+}
+
+// TestCacheUpdatesHeaders checks that the headers of a 304 Not Modified, e.g. rate limits, replace the cached ones.
+func TestCacheUpdatesHeaders(t *testing.T) {
+	var validators []string
+	server := cachingServer(&validators)
+	defer server.Close()
+
+	cache := client.NewLRUCache(8)
+	cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithCache(cache))
+	for i := 0; i < 2; i++ {
+		req, err := cl.Get("/me").Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cl.Do(req, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"42", "41"}[i]; resp.Header.Get("X-RateLimit-Remaining") != want {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %q", i, resp.Header.Get("X-RateLimit-Remaining"), want)
+		}
+	}
+}
+
+// TestCacheKey checks that the responses of different accounts sharing a Cache are kept apart. Bearer tokens
+// are part of the key, while only the consumer key and token of OAuth1 headers identify the account.
+func TestCacheKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       string
+		second      string
+		revalidated bool
+	}{
+		{"same bearer token", "Bearer a", "Bearer a", true},
+		{"other bearer token", "Bearer a", "Bearer b", false},
+		{"oauth1 other nonce", `OAuth oauth_consumer_key="key", oauth_nonce="1", oauth_token="a"`, `OAuth oauth_consumer_key="key", oauth_nonce="2", oauth_token="a"`, true},
+		{"oauth1 other token", `OAuth oauth_consumer_key="key", oauth_nonce="1", oauth_token="a"`, `OAuth oauth_consumer_key="key", oauth_nonce="1", oauth_token="b"`, false},
+		{"oauth1 other consumer", `OAuth oauth_consumer_key="key", oauth_token="a"`, `OAuth oauth_consumer_key="other", oauth_token="a"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validators []string
+			server := cachingServer(&validators)
+			defer server.Close()
+
+			cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithCache(client.NewLRUCache(8)))
+			for _, auth := range []string{tt.first, tt.second} {
+				auth := auth
+				signed := cl.Sign(func(req *http.Request) error {
+					req.Header.Set("Authorization", auth)
+					return nil
+				})
+				req, err := signed.Get("/me").Request()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := signed.Do(req, nil, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if revalidated := validators[1] != "|"; revalidated != tt.revalidated {
+				t.Errorf("second request revalidated = %v, want %v", revalidated, tt.revalidated)
+			}
+		})
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := client.NewLRUCache(2)
+	cache.Set("a", &client.CacheEntry{ETag: "a"})
+	cache.Set("b", &client.CacheEntry{ETag: "b"})
+	// a becomes the most recently used entry, so b is evicted
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("a not cached")
+	}
+	cache.Set("c", &client.CacheEntry{ETag: "c"})
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.Get(key); ok != want {
+			t.Errorf("Get(%q) cached = %v, want %v", key, ok, want)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+
+	cache.Set("a", &client.CacheEntry{ETag: "updated"})
+	if entry, _ := cache.Get("a"); entry.ETag != "updated" || cache.Len() != 2 {
+		t.Errorf("updated entry = %+v with %d entries, want the updated entry and 2 entries", entry, cache.Len())
+	}
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok || cache.Len() != 1 {
+		t.Errorf("a cached after Delete, %d entries", cache.Len())
+	}
+}
+
+// TestDiskCache checks that the entries survive a new DiskCache on the same directory and that no temporary
+// files are left behind.
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir() + "/cache"
+	entry := &client.CacheEntry{
+		StatusCode:   http.StatusOK,
+		Header:       http.Header{"Content-Type": {"application/json"}},
+		Body:         []byte(`{"name":"go-social"}`),
+		ETag:         `"v1"`,
+		LastModified: lastModified,
+		StoredAt:     time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+	}
+	client.NewDiskCache(dir).Set("GET https://api.example.com/me", entry)
+	client.NewDiskCache(dir).Set("GET https://api.example.com/me", entry)
+
+	cache := client.NewDiskCache(dir)
+	got, ok := cache.Get("GET https://api.example.com/me")
+	if !ok {
+		t.Fatal("entry not found by a new DiskCache")
+	}
+	if !bytes.Equal(got.Body, entry.Body) || got.ETag != entry.ETag || got.LastModified != entry.LastModified ||
+		got.Header.Get("Content-Type") != "application/json" || !got.StoredAt.Equal(entry.StoredAt) {
+		t.Errorf("entry = %+v, want %+v", got, entry)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !strings.HasSuffix(files[0].Name(), ".json") {
+		t.Errorf("files = %v, want a single entry without temporary files", files)
+	}
+
+	// A corrupt entry is a cache miss
+	if err := ioutil.WriteFile(dir+"/"+files[0].Name(), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("GET https://api.example.com/me"); ok {
+		t.Error("corrupt entry returned")
+	}
+
+	cache.Delete("GET https://api.example.com/me")
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("files after Delete = %v, want none", files)
+	}
+}
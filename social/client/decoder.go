@@ -7,8 +7,16 @@ Copyright © go-social. All rights reserved.
 package client
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 // ResponseDecoder decodes http responses into struct values.
@@ -17,11 +25,158 @@ type ResponseDecoder interface {
 	Decode(resp *http.Response, v interface{}) error
 }
 
-// jsonDecoder decodes http response JSON into a JSON-tagged struct value.
-type jsonDecoder struct{}
+// JSONDecoder decodes http response JSON into a JSON-tagged struct value.
+type JSONDecoder struct{}
 
 // Decode decodes the Response Body into the value pointed to by v.
 // Caller must provide a non-nil v and close the resp.Body.
-func (d jsonDecoder) Decode(resp *http.Response, v interface{}) error {
+func (d JSONDecoder) Decode(resp *http.Response, v interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
+
+// XMLDecoder decodes http response XML into a XML-tagged struct value.
+type XMLDecoder struct{}
+
+// Decode decodes the Response Body into the value pointed to by v.
+// Caller must provide a non-nil v and close the resp.Body.
+func (d XMLDecoder) Decode(resp *http.Response, v interface{}) error {
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// FormDecoder decodes form encoded http responses, e.g. of OAuth token endpoints.
+// The value pointed to by v must be a url.Values or a map[string]string. Error responses
+// decoded into other values are returned as *RawResponseError, so their body is kept.
+type FormDecoder struct{}
+
+// Decode decodes the Response Body into the value pointed to by v.
+// Caller must provide a non-nil v and close the resp.Body.
+func (d FormDecoder) Decode(resp *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case *url.Values:
+		*v = values
+	case *map[string]string:
+		m := make(map[string]string, len(values))
+		for k := range values {
+			m[k] = values.Get(k)
+		}
+		*v = m
+	default:
+		if resp.StatusCode >= 300 {
+			return &RawResponseError{StatusCode: resp.StatusCode, ContentType: resp.Header.Get(ContentTypeHeader), Body: body}
+		}
+		return fmt.Errorf("FormDecoder: cannot decode into %T", v)
+	}
+	return nil
+}
+
+// RawResponseError is returned by the DecoderRegistry if the response could not be decoded,
+// e.g. because an API returned an HTML error page. It keeps the raw body, so it can be
+// stored in the ApiErrors of the request.
+type RawResponseError struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+func (e *RawResponseError) Error() string {
+	return fmt.Sprintf("undecodable %q response with status %d", e.ContentType, e.StatusCode)
+}
+
+// DecoderRegistry is a ResponseDecoder, which selects the decoder by the Content-Type of the response.
+// Responses without a registered decoder are decoded as JSON if the body is valid JSON.
+// Otherwise, a *RawResponseError is returned. The same is done for error responses
+// which could not be decoded by the selected decoder.
+// It is safe for concurrent use.
+type DecoderRegistry struct {
+	mu       sync.RWMutex
+	decoders map[string]ResponseDecoder
+}
+
+// NewDecoderRegistry returns a new DecoderRegistry with decoders for JSON, XML and
+// form encoded responses. Structured syntax suffixes like "+json" and "+xml" are
+// decoded with the JSON and XML decoder as well.
+func NewDecoderRegistry() *DecoderRegistry {
+	r := &DecoderRegistry{decoders: make(map[string]ResponseDecoder)}
+	r.Register(ContentTypeJSON, JSONDecoder{})
+	r.Register("+json", JSONDecoder{})
+	r.Register(ContentTypeXML, XMLDecoder{})
+	r.Register(ContentTypeTextXML, XMLDecoder{})
+	r.Register("+xml", XMLDecoder{})
+	r.Register(ContentTypeForm, FormDecoder{})
+	return r
+}
+
+// Register registers the decoder for the given media type, e.g. "application/json".
+// Media types starting with "+" register a decoder for a structured syntax suffix, e.g. "+json".
+// A previously registered decoder of the media type is replaced.
+func (r *DecoderRegistry) Register(mediaType string, decoder ResponseDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decoders[strings.ToLower(mediaType)] = decoder
+}
+
+// decoder returns the decoder registered for the given Content-Type, if any.
+func (r *DecoderRegistry) decoder(contentType string) (ResponseDecoder, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if d, ok := r.decoders[mediaType]; ok {
+		return d, true
+	}
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		d, ok := r.decoders[mediaType[i:]]
+		return d, ok
+	}
+	return nil, false
+}
+
+// Decode decodes the Response Body into the value pointed to by v with the decoder
+// registered for the Content-Type of the response.
+// Caller must provide a non-nil v and close the resp.Body.
+func (r *DecoderRegistry) Decode(resp *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	contentType := resp.Header.Get(ContentTypeHeader)
+	raw := &RawResponseError{StatusCode: resp.StatusCode, ContentType: contentType, Body: body}
+
+	decoder, ok := r.decoder(contentType)
+	if !ok {
+		if !json.Valid(body) {
+			return raw
+		}
+		decoder = JSONDecoder{}
+	}
+
+	// The response is copied, so the decoder can read the buffered body.
+	buffered := *resp
+	buffered.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err := decoder.Decode(&buffered, v); err != nil {
+		if resp.StatusCode >= 300 {
+			return raw
+		}
+		return err
+	}
+	return nil
+}
+
+// WithDecoder sets the ResponseDecoder of the client, e.g. a DecoderRegistry with additional decoders.
+func WithDecoder(decoder ResponseDecoder) Option {
+	return func(c *HttpClient) {
+		c.responseDecoder = decoder
+	}
+}
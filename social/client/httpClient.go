@@ -7,14 +7,20 @@ Copyright © go-social. All rights reserved.
 package client
 
 import (
+	"context"
 	"github.com/google/go-querystring/query"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
-// HttpClient is an HTTP Request builder and sender.
+// HttpClient is an immutable HTTP Request builder and sender. Every builder method returns
+// a modified copy and leaves the receiver untouched, so a HttpClient can be shared by
+// multiple goroutines and used as the base for any number of requests.
 type HttpClient struct {
 	// http HttpClient for doing requests
 	httpClient *http.Client
@@ -22,121 +28,270 @@ type HttpClient struct {
 	method string
 	// raw url string for the requests
 	rawURL string
+	// base url set with WithBaseURL, which replaces the host of every base
+	baseOverride string
 	// stores key-values pairs to add to request's Headers
 	header http.Header
 	// url tagged query structs
 	query []interface{}
-	// HTTP Body
-	body io.Reader
+	// provides the HTTP Body and its Content-Type
+	bodyProvider BodyProvider
 	// response decoder: default json decoder
 	responseDecoder ResponseDecoder
+	// signs the request before every attempt
+	signer RequestSigner
+	// retry policy: default no retries
+	retryPolicy *RetryPolicy
+	// keeps track of the rate limits: default tracking only, without delaying requests
+	rateLimiter *RateLimiter
+	// stores responses for conditional requests: default no caching
+	cache Cache
+	// name of the provider and the path of the endpoint, used for logging
+	provider string
+	endpoint string
+	// logs the requests: default no logging
+	logger *slog.Logger
+	// dumps the requests and responses to the logger
+	debug bool
+	// traces and measures the requests: default no-op
+	telemetry *telemetry
+	// wraps the http.Client sending the requests
+	middlewares []Middleware
 }
 
+// RequestSigner signs a http.Request, e.g. by setting the Authorization header.
+type RequestSigner func(req *http.Request) error
+
 // NewHttpClient returns a new http client with a http DefaultClient.
-func NewHttpClient() *HttpClient {
-	return &HttpClient{
+// The given options are applied to the returned client.
+func NewHttpClient(opts ...Option) *HttpClient {
+	c := &HttpClient{
 		httpClient:      http.DefaultClient,
 		method:          http.MethodGet,
 		header:          make(http.Header),
 		query:           make([]interface{}, 0),
-		responseDecoder: jsonDecoder{},
+		responseDecoder: NewDecoderRegistry(),
+		rateLimiter:     newTracker(),
+		telemetry:       newTelemetry(),
 	}
+	return c.Options(opts...)
 }
 
-// New parentClient := client.New().Base("https://api.de/")
-// childClient1 := parentClient.New().Get("foo/")
-// childClient2 := parentClient.New().POST("bar/")
+// Options returns a copy of the HttpClient with the given options applied.
+func (c *HttpClient) Options(opts ...Option) *HttpClient {
+	c = c.New()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c
+}
+
+// New returns a copy of the HttpClient. Since every builder method returns a copy anyway,
+// calling New is only required for obtaining a distinct instance.
+//
+// parentClient := client.NewHttpClient().Base("https://api.io/")
+// childClient1 := parentClient.Get("foo/")
+// childClient2 := parentClient.Post("bar/")
 //
 // childClient1 and childClient2 will both use the same client with the same host
 // but will send request to https://api.io/foo/ and https://api.io/bar/.
-//
-// If pointer values are used in client, mutating the parent original parent client
-// will mutate the properties of child as well.
+// The http.Client, RetryPolicy and RateLimiter are shared by all copies.
+// Middlewares are copied, so middlewares added to a child are not used by its parent.
 func (c *HttpClient) New() *HttpClient {
-	// copy Headers pairs into new Header map
-	headerCopy := make(http.Header)
-	for k, v := range c.header {
-		headerCopy[k] = v
+	// copy Headers pairs into new Header map, so adding values does not affect the parent
+	headerCopy := c.header.Clone()
+	if headerCopy == nil {
+		headerCopy = make(http.Header)
 	}
 	return &HttpClient{
 		httpClient:      c.httpClient,
 		method:          c.method,
 		rawURL:          c.rawURL,
+		baseOverride:    c.baseOverride,
 		header:          headerCopy,
 		query:           append([]interface{}{}, c.query...),
+		bodyProvider:    c.bodyProvider,
 		responseDecoder: c.responseDecoder,
+		signer:          c.signer,
+		retryPolicy:     c.retryPolicy,
+		rateLimiter:     c.rateLimiter,
+		cache:           c.cache,
+		provider:        c.provider,
+		endpoint:        c.endpoint,
+		logger:          c.logger,
+		debug:           c.debug,
+		telemetry:       c.telemetry,
+		middlewares:     append([]Middleware(nil), c.middlewares...),
 	}
 }
 
-// Base sets the rawURL.
+// Base returns a copy of the HttpClient with the given rawURL.
+// If a base url was set with WithBaseURL, its scheme and host replace the ones of the given rawURL.
 func (c *HttpClient) Base(rawURL string) *HttpClient {
-	c.rawURL = rawURL
+	c = c.New()
+	c.rawURL = c.redirect(rawURL)
 	return c
 }
 
-// Body sets the HttpClient's body.
+// redirect returns the given absolute url with the scheme and host of the base url set with WithBaseURL, if any.
+// The path of the base url is prepended to the path of the given url.
+func (c *HttpClient) redirect(rawURL string) string {
+	if c.baseOverride == "" {
+		return rawURL
+	}
+	override, overrideErr := url.Parse(c.baseOverride)
+	u, err := url.Parse(rawURL)
+	if overrideErr != nil || err != nil || !u.IsAbs() {
+		return rawURL
+	}
+
+	u.Scheme, u.Host = override.Scheme, override.Host
+	u.Path = strings.TrimSuffix(override.Path, "/") + u.Path
+	u.RawPath = ""
+	return u.String()
+}
+
+// Provider returns a copy of the HttpClient with the given provider name, e.g. used for logging.
+func (c *HttpClient) Provider(name string) *HttpClient {
+	c = c.New()
+	c.provider = name
+	return c
+}
+
+// Body returns a copy of the HttpClient with the given body.
 // If the provided body is also an io.Closer, the request Body will be closed
 // by http.Client methods.
 func (c *HttpClient) Body(body io.Reader) *HttpClient {
 	if body == nil {
 		return c
 	}
-	c.body = body
+	return c.BodyProvider(bodyProvider{body: body})
+}
 
+// BodyProvider returns a copy of the HttpClient with the given BodyProvider. The Content-Type of the
+// BodyProvider is set on the request.
+func (c *HttpClient) BodyProvider(body BodyProvider) *HttpClient {
+	if body == nil {
+		return c
+	}
+	c = c.New()
+	c.bodyProvider = body
 	return c
 }
 
-// Path extends the rawURL with the given path.
+// BodyJSON returns a copy of the HttpClient with the body set to the JSON encoding of the given value.
+func (c *HttpClient) BodyJSON(v interface{}) *HttpClient {
+	if v == nil {
+		return c
+	}
+	return c.BodyProvider(JSONBody(v))
+}
+
+// BodyForm returns a copy of the HttpClient with the body set to the form encoding of the given url.Values or url tagged struct.
+func (c *HttpClient) BodyForm(v interface{}) *HttpClient {
+	if v == nil {
+		return c
+	}
+	return c.BodyProvider(FormBody(v))
+}
+
+// Path returns a copy of the HttpClient with the rawURL extended by the given path.
 // If parsing errors occur, the rawURL is left unmodified.
 func (c *HttpClient) Path(path string) *HttpClient {
+	c = c.New()
+	c.resolve(path)
+	return c
+}
+
+// resolve resolves the given path against the rawURL of the HttpClient.
+func (c *HttpClient) resolve(path string) {
 	baseURL, baseErr := url.Parse(c.rawURL)
 	pathURL, pathErr := url.Parse(path)
 	if baseErr == nil && pathErr == nil {
 		c.rawURL = baseURL.ResolveReference(pathURL).String()
+		c.endpoint = path
 	}
-	return c
 }
 
+// AddQuery returns a copy of the HttpClient with the given url tagged query struct added.
 func (c *HttpClient) AddQuery(query interface{}) *HttpClient {
-	if query != nil {
-		c.query = append(c.query, query)
+	if query == nil {
+		return c
 	}
+	c = c.New()
+	c.query = append(c.query, query)
 	return c
 }
 
+// Decoder returns a copy of the HttpClient with the given ResponseDecoder.
 func (c *HttpClient) Decoder(coder ResponseDecoder) *HttpClient {
+	c = c.New()
 	c.responseDecoder = coder
 	return c
 }
 
-// Get sets the Clients method to GET and sets the given pathURL.
+// Sign returns a copy of the HttpClient with the RequestSigner which signs the request right before it is sent.
+// Since the request is signed again on every retry, signatures containing a nonce or timestamp stay valid.
+func (c *HttpClient) Sign(signer RequestSigner) *HttpClient {
+	c = c.New()
+	c.signer = signer
+	return c
+}
+
+// Get returns a copy of the HttpClient with the method GET and the given pathURL.
 func (c *HttpClient) Get(pathURL string) *HttpClient {
-	c.method = "GET"
-	return c.Path(pathURL)
+	return c.withMethod(http.MethodGet, pathURL)
 }
 
-// Post sets the Clients method to POST and sets the given pathURL.
+// Post returns a copy of the HttpClient with the method POST and the given pathURL.
 func (c *HttpClient) Post(pathURL string) *HttpClient {
-	c.method = "POST"
-	return c.Path(pathURL)
+	return c.withMethod(http.MethodPost, pathURL)
 }
 
-// Put sets the Clients method to PUT and sets the given pathURL.
+// Put returns a copy of the HttpClient with the method PUT and the given pathURL.
 func (c *HttpClient) Put(pathURL string) *HttpClient {
-	c.method = "PUT"
-	return c.Path(pathURL)
+	return c.withMethod(http.MethodPut, pathURL)
 }
 
-// Patch sets the Clients method to PATCH and sets the given pathURL.
+// Patch returns a copy of the HttpClient with the method PATCH and the given pathURL.
 func (c *HttpClient) Patch(pathURL string) *HttpClient {
-	c.method = "PATCH"
-	return c.Path(pathURL)
+	return c.withMethod(http.MethodPatch, pathURL)
 }
 
-// Delete sets the Clients method to DELETE and sets the given pathURL.
+// Delete returns a copy of the HttpClient with the method DELETE and the given pathURL.
 func (c *HttpClient) Delete(pathURL string) *HttpClient {
-	c.method = "DELETE"
-	return c.Path(pathURL)
+	return c.withMethod(http.MethodDelete, pathURL)
+}
+
+// withMethod returns a copy of the HttpClient with the given method and pathURL.
+func (c *HttpClient) withMethod(method, pathURL string) *HttpClient {
+	c = c.New()
+	c.method = method
+	c.resolve(pathURL)
+	return c
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, which is resolved against the rawURL
+// of the client. The rate limits are tracked per endpoint by the RateLimiter, which is shared by all copies
+// of the client, so this is only a best-effort value: concurrent requests to the same endpoint may have
+// updated it in the meantime. Use CaptureResponse for the rate limit of a specific response.
+// Returns false if the API did not send any rate limit information for the endpoint.
+func (c *HttpClient) RateLimit(endpoint string) (RateLimit, bool) {
+	if c.rateLimiter == nil {
+		return RateLimit{}, false
+	}
+	reqURL, err := url.Parse(c.rawURL)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	pathURL, err := url.Parse(endpoint)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reqURL = reqURL.ResolveReference(pathURL)
+	return c.rateLimiter.RateLimit(reqURL.Host + reqURL.Path)
 }
 
 // Request returns a new http.Request with the HttpClient properties.
@@ -153,25 +308,40 @@ func (c *HttpClient) Request() (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(c.method, reqURL.String(), c.body)
+	var body io.Reader
+	if c.bodyProvider != nil {
+		body, err = c.bodyProvider.Body()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(c.method, reqURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
 	addHeaders(req, c.header)
+	if c.bodyProvider != nil {
+		if contentType := c.bodyProvider.ContentType(); contentType != "" {
+			req.Header.Set(ContentTypeHeader, contentType)
+		}
+	}
 	return req, err
 }
 
-// Add adds the key, value pair in Headers, appending values for existing keys
-// to the key's values. Header keys are canonicalized.
+// Add returns a copy of the HttpClient with the key, value pair added to the Headers, appending values
+// for existing keys to the key's values. Header keys are canonicalized.
 func (c *HttpClient) Add(key, value string) *HttpClient {
+	c = c.New()
 	c.header.Add(key, value)
 	return c
 }
 
-// Set sets the key, value pair in Headers, replacing existing values
-// associated with key. Header keys are canonicalized.
+// Set returns a copy of the HttpClient with the key, value pair set in the Headers, replacing existing
+// values associated with key. Header keys are canonicalized.
 func (c *HttpClient) Set(key, value string) *HttpClient {
+	c = c.New()
 	c.header.Set(key, value)
 	return c
 }
@@ -215,31 +385,26 @@ func addHeaders(req *http.Request, header http.Header) {
 // are JSON decoded into the value pointed to by successV and other responses
 // are JSON decoded into the value pointed to by failureV.
 // If the status code of response is 204(no content), decoding is skipped.
+// The request is signed and retried according to the RequestSigner and RetryPolicy of the client.
 // Any error sending the request or decoding the response is returned.
 func (c *HttpClient) Do(req *http.Request, success interface{}, failure interface{}) (*http.Response, error) {
-	resp, err := c.httpClient.Do(req)
+	req, span := c.startSpan(req)
+	start := time.Now()
+	resp, attempts, err := c.send(req)
+	latency := time.Since(start)
+	c.endSpan(req, span, resp, attempts, latency, err)
+	c.logRequest(req, resp, attempts, latency, err)
 	if err != nil {
 		return resp, err
 	}
+	if err := captureResponse(req, resp); err != nil {
+		resp.Body.Close()
+		return resp, err
+	}
 
 	// when err is nil, resp contains a non-nil resp.Body which must be closed
 	defer resp.Body.Close()
 
-	//Only for debugging purposes
-	/*reqDump, err := httputil.DumpRequestOut(req, true)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-	bodyString := string(bodyBytes)
-	fmt.Println(bodyString)*/
-
 	// The default HTTP client'c Transport may not
 	// reuse HTTP/1.x "keep-alive" TCP connections if the Body is
 	// not read to completion and closed. So simply dump it afterwards.
@@ -258,6 +423,28 @@ func (c *HttpClient) Do(req *http.Request, success interface{}, failure interfac
 	return resp, err
 }
 
+// send sends the request through the middleware chain of the client and returns the number of attempts
+// along with the last response. See chain for the middlewares signing, retrying and caching the request.
+func (c *HttpClient) send(req *http.Request) (*http.Response, int, error) {
+	attempts := 0
+	req = req.WithContext(context.WithValue(req.Context(), attemptsKey{}, &attempts))
+	resp, err := c.chain().Do(req)
+	return resp, attempts, err
+}
+
+// sign returns a Middleware signing every attempt with the RequestSigner of the client.
+func (c *HttpClient) sign(next Doer) Doer {
+	if c.signer == nil {
+		return next
+	}
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := c.signer(req); err != nil {
+			return nil, abortError{err}
+		}
+		return next.Do(req)
+	})
+}
+
 // decodeResponse decodes response Body into the value pointed to by successV
 // if the response is a success (2XX) or into the value pointed to by failureV
 // otherwise. If the successV or failureV argument to decode into is nil,
@@ -0,0 +1,167 @@
+/*
+httpClient_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/twitter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const stressGoroutines = 64
+
+// echo is the response of echoHandler.
+type echo struct {
+	Path  string              `json:"path"`
+	Query map[string][]string `json:"query"`
+	Team  []string            `json:"team"`
+}
+
+// echoHandler answers with the path, the query and the X-Team header of the request.
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(echo{Path: r.URL.Path, Query: r.URL.Query(), Team: r.Header.Values("X-Team")})
+}
+
+// TestHttpClientConcurrentCopies sends requests of copies of a shared HttpClient from many goroutines
+// and checks that the path, query and headers of one copy never leak into another one. Run with -race.
+func TestHttpClientConcurrentCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(echoHandler))
+	defer server.Close()
+
+	type params struct {
+		ID int `url:"id"`
+	}
+	base := client.NewHttpClient().Base(server.URL).Set("X-Team", "base").AddQuery(&params{ID: -1})
+
+	var wg sync.WaitGroup
+	for i := 0; i < stressGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("/items/%d", i)
+			c := base.Get(path).AddQuery(&params{ID: i}).Add("X-Team", strconv.Itoa(i))
+
+			req, err := c.Request()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var got echo
+			if _, err := c.Do(req, &got, nil); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got.Path != path {
+				t.Errorf("path = %q, want %q", got.Path, path)
+			}
+			if want := []string{"-1", strconv.Itoa(i)}; fmt.Sprint(got.Query["id"]) != fmt.Sprint(want) {
+				t.Errorf("%s: id = %v, want %v", path, got.Query["id"], want)
+			}
+			if want := []string{"base", strconv.Itoa(i)}; fmt.Sprint(got.Team) != fmt.Sprint(want) {
+				t.Errorf("%s: X-Team = %v, want %v", path, got.Team, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	req, err := base.Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := req.URL.Query()["id"]; len(q) != 1 || len(req.Header.Values("X-Team")) != 1 {
+		t.Errorf("base client was modified: query %v, header %v", req.URL.Query(), req.Header)
+	}
+}
+
+// TestProviderClientConcurrentRequests sends the follower and following requests of a single Twitter client
+// from many goroutines against a test server. Each goroutine uses its own user id and cursor, which the server
+// echoes back along with the endpoint and a rate limit, so leaking queries, paths or rate limits between the
+// calls are detected. Run with -race.
+func TestProviderClientConcurrentRequests(t *testing.T) {
+	endpoints := map[string]int64{
+		twitter.FollowerIdsPath:  1,
+		twitter.FollowingIdsPath: 2,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		kind, ok := endpoints[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		if len(query["user_id"]) != 1 || len(query["cursor"]) != 1 {
+			http.Error(w, "leaked query: "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get(oauth1.AuthorizationHeaderName) == "" {
+			http.Error(w, "unsigned request", http.StatusUnauthorized)
+			return
+		}
+		userID, _ := strconv.ParseInt(query.Get("user_id"), 10, 64)
+		cursor, _ := strconv.ParseInt(query.Get("cursor"), 10, 64)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Rate-Limit-Limit", "1000")
+		w.Header().Set("X-Rate-Limit-Remaining", strconv.FormatInt(userID, 10))
+		json.NewEncoder(w).Encode(twitter.UserFollowerIDs{IDs: []int64{userID, cursor}, NextCursor: kind})
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := twitter.NewClient(context.Background(), cred, oauth1.NewToken("token", "token-secret"), client.WithBaseURL(server.URL))
+
+	var wg sync.WaitGroup
+	for i := 1; i <= stressGoroutines; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			params := &twitter.FollowerIDParams{UserID: i, Cursor: i * 100}
+
+			fetch, kind := c.Follower.FollowerIDsContext, endpoints[twitter.FollowerIdsPath]
+			if i%2 == 0 {
+				fetch, kind = c.Follower.FollowingIDsContext, endpoints[twitter.FollowingIdsPath]
+			}
+
+			var resp client.Response
+			ids, err := fetch(client.CaptureResponse(context.Background(), &resp), params)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(ids.IDs) != 2 || ids.IDs[0] != i || ids.IDs[1] != i*100 {
+				t.Errorf("user %d: ids = %v, want [%d %d]", i, ids.IDs, i, i*100)
+			}
+			if ids.NextCursor != kind {
+				t.Errorf("user %d: request sent to the wrong endpoint %d, want %d", i, ids.NextCursor, kind)
+			}
+			if !resp.HasRateLimit || resp.RateLimit.Remaining != int(i) {
+				t.Errorf("user %d: rate limit = %+v, want remaining %d", i, resp.RateLimit, i)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+
+	for endpoint := range endpoints {
+		if _, ok := c.RateLimit(endpoint); !ok {
+			t.Errorf("no rate limit tracked for %s", endpoint)
+		}
+	}
+	if _, ok := c.RateLimit(twitter.UserPath); ok {
+		t.Errorf("rate limit tracked for %s without a request", twitter.UserPath)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+logging.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"time"
+)
+
+// Redacted replaces secrets in logs and dumps.
+const Redacted = "[REDACTED]"
+
+var (
+	// secretHeader matches header lines of dumps carrying credentials
+	secretHeader = regexp.MustCompile(`(?im)^((?:Proxy-)?Authorization|Cookie|Set-Cookie):[^\r\n]*`)
+	// secretParam matches secret query and form parameters, e.g. oauth_signature=... The token of revoke requests
+	// and the authorization code of code exchanges are matched as well.
+	secretParam = regexp.MustCompile(`\b(oauth_signature|oauth_token_secret|client_secret|refresh_token|access_token|code_verifier|token|code)=[^&\s"]*`)
+	// secretField matches secret fields of JSON bodies, e.g. "refresh_token": "..."
+	secretField = regexp.MustCompile(`"(client_secret|refresh_token|access_token|oauth_token_secret)"\s*:\s*"[^"]*"`)
+)
+
+// WithLogger logs every request with the provider, method, endpoint, status, attempts and latency to the given logger.
+// Failed requests are logged with level warn, successful ones with level info. A nil logger disables logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *HttpClient) {
+		c.logger = logger
+	}
+}
+
+// WithDebug dumps every request and response with level debug, including their bodies.
+// The Authorization header and secrets like oauth_signature, client_secret, refresh_token and code are redacted.
+// Dumps are written to the logger of the client or slog.Default if none is set.
+func WithDebug() Option {
+	return func(c *HttpClient) {
+		c.debug = true
+	}
+}
+
+// Redact replaces the credentials in the given dump or url with Redacted.
+func Redact(s string) string {
+	s = secretHeader.ReplaceAllString(s, "$1: "+Redacted)
+	s = secretParam.ReplaceAllString(s, "$1="+Redacted)
+	return secretField.ReplaceAllString(s, `"$1":"`+Redacted+`"`)
+}
+
+// debugLogger returns the logger for dumps or nil if debugging is disabled.
+func (c *HttpClient) debugLogger() *slog.Logger {
+	if !c.debug {
+		return nil
+	}
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
+}
+
+// endpointOf returns the endpoint of the request, which is the path passed to the client if any.
+func (c *HttpClient) endpointOf(req *http.Request) string {
+	if c.endpoint != "" {
+		return Redact(c.endpoint)
+	}
+	return req.URL.Path
+}
+
+// logRequest logs the result of the request.
+func (c *HttpClient) logRequest(req *http.Request, resp *http.Response, attempts int, latency time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("provider", c.provider),
+		slog.String("method", req.Method),
+		slog.String("endpoint", c.endpointOf(req)),
+		slog.Int("attempts", attempts),
+		slog.Duration("latency", latency),
+	}
+	level := slog.LevelInfo
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.Header.Get(FromCacheHeader) != "" {
+			attrs = append(attrs, slog.Bool("cached", true))
+		}
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", Redact(err.Error())))
+		level = slog.LevelWarn
+	}
+	c.logger.LogAttrs(req.Context(), level, "go-social request", attrs...)
+}
+
+// dump returns a Middleware dumping the signed request and its response if debugging is enabled.
+func (c *HttpClient) dump(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		c.dumpRequest(req)
+		resp, err := next.Do(req)
+		if resp != nil {
+			c.dumpResponse(req, resp)
+		}
+		return resp, err
+	})
+}
+
+// dumpRequest dumps the signed request if debugging is enabled.
+func (c *HttpClient) dumpRequest(req *http.Request) {
+	logger := c.debugLogger()
+	if logger == nil || !logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		logger.DebugContext(req.Context(), "go-social request dump failed", slog.String("error", err.Error()))
+		return
+	}
+	logger.DebugContext(req.Context(), "go-social request dump",
+		slog.String("provider", c.provider),
+		slog.String("endpoint", c.endpointOf(req)),
+		slog.String("dump", Redact(string(dump))),
+	)
+}
+
+// dumpResponse dumps the response if debugging is enabled. The body of the response is restored afterwards.
+func (c *HttpClient) dumpResponse(req *http.Request, resp *http.Response) {
+	logger := c.debugLogger()
+	if logger == nil || !logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		logger.DebugContext(req.Context(), "go-social response dump failed", slog.String("error", err.Error()))
+		return
+	}
+	logger.DebugContext(req.Context(), "go-social response dump",
+		slog.String("provider", c.provider),
+		slog.String("endpoint", c.endpointOf(req)),
+		slog.Int("status", resp.StatusCode),
+		slog.String("dump", Redact(string(dump))),
+	)
+}
@@ -0,0 +1,173 @@
+/*
+logging_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/twitch"
+	"github.com/emrearmagan/go-social/social/youtube"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The secrets sent and received by the token requests. None of them must show up in the logs.
+var secrets = []string{
+	"client-secret-value",
+	"access-token-value",
+	"refresh-token-value",
+	"auth-code-value",
+	"code-verifier-value",
+	"new-access-token-value",
+	"new-refresh-token-value",
+	base64.StdEncoding.EncodeToString([]byte("client-id:client-secret-value")),
+}
+
+// handlerTransport serves all requests with the handler, regardless of their host.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// tokenHandler answers every request with a new token.
+func tokenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"access_token":"new-access-token-value","refresh_token":"new-refresh-token-value","token_type":"bearer","expires_in":3600}`))
+}
+
+// debugOptions returns the options logging all requests and dumps to the returned buffer.
+func debugOptions() (*bytes.Buffer, []client.Option) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return buf, []client.Option{
+		client.WithTransport(handlerTransport{handler: http.HandlerFunc(tokenHandler)}),
+		client.WithLogger(logger),
+		client.WithDebug(),
+	}
+}
+
+// TestDumpsRedactTokenRequests checks that the dumps of the refresh, revoke and code exchange requests
+// and their responses contain no secrets.
+func TestDumpsRedactTokenRequests(t *testing.T) {
+	cred := &oauth.Credentials{ConsumerKey: "client-id", ConsumerSecret: "client-secret-value"}
+	token := oauth2.NewToken("access-token-value", "refresh-token-value")
+
+	tests := []struct {
+		name string
+		call func(opts []client.Option) error
+	}{
+		{
+			name: "twitch refresh",
+			call: func(opts []client.Option) error {
+				_, err := twitch.NewClient(context.Background(), cred, token, opts...).RefreshToken()
+				return err
+			},
+		},
+		{
+			name: "twitch revoke",
+			call: func(opts []client.Option) error {
+				return twitch.NewClient(context.Background(), cred, token, opts...).Revoke()
+			},
+		},
+		{
+			name: "youtube revoke",
+			call: func(opts []client.Option) error {
+				return youtube.NewClient(context.Background(), cred, token, opts...).Revoke()
+			},
+		},
+		{
+			name: "exchange with params",
+			call: func(opts []client.Option) error {
+				a := oauth2.NewOAuth(context.Background(), cred, nil, client.NewHttpClient(opts...)).
+					Endpoint(oauth2.Endpoint{TokenURL: "https://example.com/token", AuthStyle: oauth2.AuthStyleInParams})
+				_, err := a.Exchange(context.Background(), "auth-code-value", "code-verifier-value")
+				return err
+			},
+		},
+		{
+			name: "exchange with basic auth",
+			call: func(opts []client.Option) error {
+				a := oauth2.NewOAuth(context.Background(), cred, nil, client.NewHttpClient(opts...)).
+					Endpoint(oauth2.Endpoint{TokenURL: "https://example.com/token", AuthStyle: oauth2.AuthStyleInHeader})
+				_, err := a.Exchange(context.Background(), "auth-code-value", "code-verifier-value")
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, opts := debugOptions()
+			if err := tt.call(opts); err != nil {
+				t.Fatal(err)
+			}
+
+			logs := buf.String()
+			if !strings.Contains(logs, "go-social request dump") || !strings.Contains(logs, "go-social response dump") {
+				t.Fatalf("missing dumps in logs:\n%s", logs)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(logs, secret) {
+					t.Errorf("logs contain the secret %q:\n%s", secret, logs)
+				}
+			}
+		})
+	}
+}
+
+// TestDumpIncludesMiddlewareHeaders checks that the request is dumped after the middlewares registered with Use,
+// like the Client-Id middleware of Twitch, so the dump shows the request as it is sent.
+func TestDumpIncludesMiddlewareHeaders(t *testing.T) {
+	cred := &oauth.Credentials{ConsumerKey: "client-id", ConsumerSecret: "client-secret-value"}
+	token := oauth2.NewToken("access-token-value", "refresh-token-value")
+	buf, opts := debugOptions()
+	opts = append(opts, client.WithMiddleware(client.SetHeader("X-Trace-Id", "trace-1")))
+
+	if _, err := twitch.NewClient(context.Background(), cred, token, opts...).User.UserCredentials(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := buf.String()
+	for _, header := range []string{twitch.ClientHeaderName + ": client-id", "X-Trace-Id: trace-1"} {
+		if !strings.Contains(logs, header) {
+			t.Errorf("request dump is missing %q:\n%s", header, logs)
+		}
+	}
+	if strings.Contains(logs, "access-token-value") {
+		t.Errorf("logs contain the access token:\n%s", logs)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/revoke?token=abc&client_id=id", "/revoke?token=[REDACTED]&client_id=id"},
+		{"grant_type=authorization_code&code=abc&code_verifier=def", "grant_type=authorization_code&code=[REDACTED]&code_verifier=[REDACTED]"},
+		{"oauth_token=public&oauth_token_secret=abc", "oauth_token=public&oauth_token_secret=[REDACTED]"},
+		{"error_code=42", "error_code=42"},
+		{`{"access_token": "abc", "scope": "read"}`, `{"access_token":"[REDACTED]", "scope": "read"}`},
+		{"Authorization: Bearer abc\r\nAccept: */*", "Authorization: [REDACTED]\r\nAccept: */*"},
+	}
+	for _, tt := range tests {
+		if got := client.Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
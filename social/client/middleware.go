@@ -0,0 +1,84 @@
+/*
+middleware.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"net/http"
+)
+
+// Doer sends a http request and returns its response, e.g. a http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer sending the requests, e.g. for injecting headers or auditing requests.
+// The features of the HttpClient are middlewares as well, see chain. Middlewares registered with Use
+// are the innermost ones, so they are called for every attempt with the signed request, right before it is sent.
+type Middleware func(next Doer) Doer
+
+// Use returns a copy of the HttpClient with the given middlewares appended to its chain.
+// The first middleware of the chain is the outermost one, so it is called first.
+func (c *HttpClient) Use(middlewares ...Middleware) *HttpClient {
+	c = c.New()
+	for _, mw := range middlewares {
+		if mw != nil {
+			c.middlewares = append(c.middlewares, mw)
+		}
+	}
+	return c
+}
+
+// WithMiddleware appends the given middlewares to the chain of the client.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *HttpClient) {
+		for _, mw := range middlewares {
+			if mw != nil {
+				c.middlewares = append(c.middlewares, mw)
+			}
+		}
+	}
+}
+
+// chain returns the http.Client of the client wrapped by its middlewares. From the outermost to the innermost:
+//   - retry sends the request again according to the RetryPolicy
+//   - waitRateLimit delays the request by the RateLimiter
+//   - sign signs every attempt with the RequestSigner
+//   - revalidateCached sends conditional requests and serves 304 responses from the Cache
+//   - trackRateLimit updates the RateLimiter with the rate limit headers
+//   - the middlewares registered with Use
+//   - dump dumps the request and the response for debugging, right before the transport, so headers
+//     added by the middlewares are included
+func (c *HttpClient) chain() Doer {
+	middlewares := append([]Middleware{c.retry, c.waitRateLimit, c.sign, c.revalidateCached, c.trackRateLimit}, c.middlewares...)
+	middlewares = append(middlewares, c.dump)
+
+	var d Doer = c.httpClient
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		d = middlewares[i](d)
+	}
+	return d
+}
+
+// SetHeader returns a Middleware, which sets the header to the given value unless the request already has it.
+func SetHeader(key, value string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(key) == "" {
+				req.Header.Set(key, value)
+			}
+			return next.Do(req)
+		})
+	}
+}
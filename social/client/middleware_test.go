@@ -0,0 +1,114 @@
+/*
+middleware_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMiddlewareChain checks that the middlewares registered with Use see every attempt of the retry
+// with a fresh signature and the raw conditional requests of the cache.
+func TestMiddlewareChain(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch n := atomic.AddInt32(&calls, 1); {
+		case n == 1:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{}`))
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"name":"go-social"}`))
+		}
+	}))
+	defer server.Close()
+
+	var signatures int32
+	var seen []string
+	audit := func(next client.Doer) client.Doer {
+		return client.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.Do(req)
+			seen = append(seen, fmt.Sprintf("%s %s %d", req.Header.Get("X-Signature"), req.Header.Get("If-None-Match"), resp.StatusCode))
+			return resp, err
+		})
+	}
+
+	cl := client.NewHttpClient(
+		client.WithBaseURL(server.URL),
+		client.WithRetry(&client.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}),
+		client.WithCache(client.NewLRUCache(8)),
+		client.WithMiddleware(audit),
+	).Sign(func(req *http.Request) error {
+		// Like OAuth1, every attempt is signed with a new nonce
+		n := atomic.AddInt32(&signatures, 1)
+		req.Header.Set("Authorization", fmt.Sprintf(`OAuth oauth_consumer_key="key", oauth_nonce="%d", oauth_token="token"`, n))
+		req.Header.Set("X-Signature", fmt.Sprintf("sig-%d", n))
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		req, err := cl.Get("/me").Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var user struct {
+			Name string `json:"name"`
+		}
+		resp, err := cl.Do(req, &user, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || user.Name != "go-social" {
+			t.Errorf("request %d: %d %+v, want the (cached) user", i, resp.StatusCode, user)
+		}
+	}
+
+	want := []string{`sig-1  500`, `sig-2  200`, `sig-3 "v1" 304`}
+	if got := strings.Join(seen, ","); got != strings.Join(want, ",") {
+		t.Errorf("middleware saw %v, want %v", seen, want)
+	}
+}
+
+// TestSignerErrorNotRetried checks that an error of the RequestSigner is returned without sending or retrying the request.
+func TestSignerErrorNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	signErr := errors.New("no key")
+	var signatures int32
+	cl := client.NewHttpClient(
+		client.WithBaseURL(server.URL),
+		client.WithRetry(&client.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}),
+	).Sign(func(req *http.Request) error {
+		atomic.AddInt32(&signatures, 1)
+		return signErr
+	})
+
+	req, err := cl.Get("/me").Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cl.Do(req, nil, nil); !errors.Is(err, signErr) {
+		t.Errorf("error = %v, want the signer error", err)
+	}
+	if signatures != 1 || calls != 0 {
+		t.Errorf("signed %d times and sent %d requests, want 1 and 0", signatures, calls)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+options.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a HttpClient. Options are accepted by NewHttpClient, HttpClient.Options
+// and by the NewClient function of every provider package.
+type Option func(*HttpClient)
+
+// WithHTTPClient sets the http.Client used for sending the requests.
+// A nil http.Client is ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *HttpClient) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for sending the requests.
+// The current http.Client is copied beforehand, so the http.DefaultClient is never mutated.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *HttpClient) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithTimeout sets the time limit for requests made by the client.
+// The current http.Client is copied beforehand, so the http.DefaultClient is never mutated.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HttpClient) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+// WithBaseURL overrides the base url of the client. Since the provider packages apply the options
+// after setting their default base, this can be used to point a provider to e.g. a local httptest.Server.
+// Requests to the other bases of a provider, like its token, refresh and revoke endpoints or the UserInfo
+// of YouTube, are sent to the scheme and host of the given url as well, keeping their paths.
+func WithBaseURL(rawURL string) Option {
+	return func(c *HttpClient) {
+		c.rawURL = rawURL
+		c.baseOverride = rawURL
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ratelimit.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/emrearmagan/go-social/models/errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit represents the rate limit of the current window as announced by the API.
+type RateLimit struct {
+	// Limit is the maximum number of requests allowed in the current window.
+	Limit int `json:"limit"`
+	// Remaining is the number of requests left in the current window.
+	Remaining int `json:"remaining"`
+	// Reset is the time at which the current window resets.
+	Reset time.Time `json:"reset"`
+}
+
+// Exhausted returns true if no requests are left in the current window.
+func (r RateLimit) Exhausted() bool {
+	return r.Remaining <= 0 && time.Now().Before(r.Reset)
+}
+
+// The rate limit headers of the APIs. Each API uses a slightly different one.
+var (
+	limitHeaders = []string{
+		"X-Rate-Limit-Limit", // Twitter
+		"X-RateLimit-Limit",  // GitHub, Dribbble
+		"Ratelimit-Limit",    // Twitch
+	}
+	remainingHeaders = []string{
+		"X-Rate-Limit-Remaining", // Twitter
+		"X-RateLimit-Remaining",  // GitHub, Dribbble, Reddit
+		"Ratelimit-Remaining",    // Twitch
+	}
+	// Reddit does not send a limit, but the used requests of the current window
+	usedHeader = "X-RateLimit-Used"
+)
+
+// ParseRateLimit parses the rate limit headers of the given response header.
+// Returns false if the header does not contain any rate limit information.
+func ParseRateLimit(header http.Header) (RateLimit, bool) {
+	remaining, ok := headerNumber(header, remainingHeaders...)
+	if !ok {
+		return RateLimit{}, false
+	}
+
+	limit, ok := headerNumber(header, limitHeaders...)
+	if !ok {
+		// Reddit: limit = used + remaining
+		if used, ok := headerNumber(header, usedHeader); ok {
+			limit = used + remaining
+		}
+	}
+	reset, _ := resetTime(header)
+
+	return RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}, true
+}
+
+// headerNumber returns the value of the first of the given headers that is set.
+// Values are parsed as float, since Reddit sends e.g. "598.0".
+func headerNumber(header http.Header, keys ...string) (int, bool) {
+	for _, key := range keys {
+		v := header.Get(key)
+		if v == "" {
+			continue
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// RateLimiter keeps track of the rate limits announced by the APIs and delays requests
+// once the budget of the current window is exhausted. The zero value is ready to use and waits
+// until the window resets. A RateLimiter is safe for concurrent use. Sharing one RateLimiter across clients
+// (e.g. a worker pool) keeps all of them below the limit.
+type RateLimiter struct {
+	// MaxWait is the maximum time a request is delayed. If the current window resets later,
+	// the request fails immediately with errors.ErrRateLimit. Zero waits until the window resets
+	// or the request's context is done.
+	MaxWait time.Duration
+
+	// trackOnly is set for limiters which only keep track of the rate limits
+	trackOnly bool
+	mu        sync.Mutex
+	limits    map[string]RateLimit
+}
+
+// NewRateLimiter returns a new RateLimiter which delays requests for at most maxWait.
+func NewRateLimiter(maxWait time.Duration) *RateLimiter {
+	return &RateLimiter{
+		MaxWait: maxWait,
+		limits:  make(map[string]RateLimit),
+	}
+}
+
+// newTracker returns a RateLimiter which only keeps track of the rate limits without delaying any requests.
+func newTracker() *RateLimiter {
+	return &RateLimiter{trackOnly: true, limits: make(map[string]RateLimit)}
+}
+
+// WithRateLimiter sets the RateLimiter of the client. Without a RateLimiter, rate limits are
+// tracked but requests are never delayed.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *HttpClient) {
+		if limiter != nil {
+			c.rateLimiter = limiter
+		}
+	}
+}
+
+// RateLimit returns the last known rate limit for the given key.
+func (l *RateLimiter) RateLimit(key string) (RateLimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.limits[key]
+	return r, ok
+}
+
+// Update sets the rate limit for the given key.
+func (l *RateLimiter) Update(key string, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limits == nil {
+		l.limits = make(map[string]RateLimit)
+	}
+	l.limits[key] = limit
+}
+
+// Wait blocks until a request for the given key can be sent without exceeding the rate limit.
+// Every call reserves one request of the remaining budget.
+func (l *RateLimiter) Wait(ctx context.Context, key string) error {
+	if l.trackOnly {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		r, ok := l.limits[key]
+		if !ok || !time.Now().Before(r.Reset) {
+			// Unknown or outdated window
+			delete(l.limits, key)
+			l.mu.Unlock()
+			return nil
+		}
+		if r.Remaining > 0 {
+			r.Remaining--
+			l.limits[key] = r
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		wait := time.Until(r.Reset)
+		if l.MaxWait > 0 && wait > l.MaxWait {
+			err := errors.New(errors.ErrRateLimit, fmt.Sprintf("rate limit for %s exhausted until %v", key, r.Reset))
+			err.RetryAfter = wait
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// rateLimitKey returns the key of the rate limit window the request belongs to.
+// Most APIs limit the requests per endpoint, so the host and the path are used.
+func rateLimitKey(req *http.Request) string {
+	return req.URL.Host + req.URL.Path
+}
+
+// waitRateLimit returns a Middleware delaying the request by the RateLimiter of the client
+// if the rate limit of the endpoint is exhausted.
+func (c *HttpClient) waitRateLimit(next Doer) Doer {
+	if c.rateLimiter == nil {
+		return next
+	}
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := c.rateLimiter.Wait(req.Context(), rateLimitKey(req)); err != nil {
+			return nil, abortError{err}
+		}
+		return next.Do(req)
+	})
+}
+
+// trackRateLimit returns a Middleware updating the rate limit of the endpoint with the rate limit headers of the response.
+func (c *HttpClient) trackRateLimit(next Doer) Doer {
+	if c.rateLimiter == nil {
+		return next
+	}
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.Do(req)
+		if resp != nil {
+			if limit, ok := ParseRateLimit(resp.Header); ok {
+				c.rateLimiter.Update(rateLimitKey(req), limit)
+			}
+		}
+		return resp, err
+	})
+}
@@ -0,0 +1,147 @@
+/*
+ratelimit_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		limit     int
+		remaining int
+		ok        bool
+	}{
+		{"twitter", http.Header{"X-Rate-Limit-Limit": {"15"}, "X-Rate-Limit-Remaining": {"14"}}, 15, 14, true},
+		{"github", http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4999"}}, 5000, 4999, true},
+		{"twitch", http.Header{"Ratelimit-Limit": {"800"}, "Ratelimit-Remaining": {"799"}}, 800, 799, true},
+		// Reddit sends the used requests instead of the limit
+		{"reddit", http.Header{"X-Ratelimit-Used": {"2"}, "X-Ratelimit-Remaining": {"598.0"}, "X-Ratelimit-Reset": {"30"}}, 600, 598, true},
+		{"no rate limit", http.Header{"Content-Type": {"application/json"}}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, ok := client.ParseRateLimit(tt.header)
+			if ok != tt.ok || limit.Limit != tt.limit || limit.Remaining != tt.remaining {
+				t.Errorf("ParseRateLimit = %+v, %v, want limit %d, remaining %d, %v", limit, ok, tt.limit, tt.remaining, tt.ok)
+			}
+		})
+	}
+}
+
+// TestRateLimiterZeroValue checks that the zero value RateLimiter can be used and delays requests.
+func TestRateLimiterZeroValue(t *testing.T) {
+	var limiter client.RateLimiter
+	if err := limiter.Wait(context.Background(), "unknown"); err != nil {
+		t.Fatal(err)
+	}
+
+	reset := time.Now().Add(50 * time.Millisecond)
+	limiter.Update("api/me", client.RateLimit{Limit: 1, Remaining: 0, Reset: reset})
+	if err := limiter.Wait(context.Background(), "api/me"); err != nil {
+		t.Fatal(err)
+	}
+	if time.Now().Before(reset) {
+		t.Error("Wait returned before the window reset")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := client.NewRateLimiter(0)
+	reset := time.Now().Add(50 * time.Millisecond)
+	limiter.Update("api/me", client.RateLimit{Limit: 2, Remaining: 1, Reset: reset})
+
+	// The remaining request is reserved by the first call
+	if err := limiter.Wait(context.Background(), "api/me"); err != nil || !time.Now().Before(reset) {
+		t.Fatalf("first Wait = %v after the reset, want no delay", err)
+	}
+	if limit, _ := limiter.RateLimit("api/me"); limit.Remaining != 0 {
+		t.Errorf("remaining = %d, want 0", limit.Remaining)
+	}
+	// Other endpoints have their own window
+	if err := limiter.Wait(context.Background(), "api/other"); err != nil || !time.Now().Before(reset) {
+		t.Fatalf("Wait for another endpoint = %v after the reset, want no delay", err)
+	}
+	if err := limiter.Wait(context.Background(), "api/me"); err != nil || time.Now().Before(reset) {
+		t.Errorf("second Wait = %v before the reset, want to block until the reset", err)
+	}
+
+	limiter.Update("api/me", client.RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour)})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "api/me"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterMaxWait(t *testing.T) {
+	limiter := client.NewRateLimiter(10 * time.Millisecond)
+	limiter.Update("api/me", client.RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+	start := time.Now()
+	err := limiter.Wait(context.Background(), "api/me")
+	if !errors.Is(err, socialErrors.ErrRateLimit) {
+		t.Fatalf("Wait = %v, want %v", err, socialErrors.ErrRateLimit)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Wait blocked for %v, want to fail immediately", time.Since(start))
+	}
+	var socialErr socialErrors.SocialError
+	if !errors.As(err, &socialErr) || socialErr.RetryAfter < 59*time.Minute {
+		t.Errorf("RetryAfter = %v, want about 1h", socialErr.RetryAfter)
+	}
+}
+
+// TestRateLimiterEndpoints checks that the client keeps the rate limits of its endpoints apart.
+func TestRateLimiterEndpoints(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/exhausted" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "3600")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithRateLimiter(client.NewRateLimiter(10*time.Millisecond)))
+	send := func(path string) error {
+		req, err := cl.Get(path).Request()
+		if err != nil {
+			return err
+		}
+		_, err = cl.Do(req, nil, nil)
+		return err
+	}
+
+	for _, path := range []string{"/exhausted", "/other"} {
+		if err := send(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := send("/exhausted"); !errors.Is(err, socialErrors.ErrRateLimit) {
+		t.Errorf("error = %v, want %v", err, socialErrors.ErrRateLimit)
+	}
+	if err := send("/other"); err != nil {
+		t.Errorf("error of another endpoint = %v, want none", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("sent %d requests, want 3", n)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+response.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+)
+
+// Response holds the meta data of a http response, which is otherwise only available decoded.
+// It is filled by every request sent with a context returned by CaptureResponse or CaptureRawResponse.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// RateLimit is the rate limit announced by the response. HasRateLimit is false, if the response
+	// has no rate limit headers.
+	RateLimit    RateLimit
+	HasRateLimit bool
+	ETag         string
+	LastModified string
+	// FromCache is true, if the response was served from the Cache after the API answered with 304 Not Modified.
+	FromCache bool
+	// Body is the raw body of the response. It is only kept if requested with CaptureRawResponse.
+	Body []byte
+}
+
+// Get returns the first value of the header with the given key, e.g. a request id.
+func (r *Response) Get(key string) string {
+	return r.Header.Get(key)
+}
+
+type responseCapture struct {
+	resp    *Response
+	rawBody bool
+}
+
+type responseCaptureKey struct{}
+
+// CaptureResponse returns a copy of the context, which captures the response of the requests sent with it
+// into the given Response. If a request is replayed, e.g. after refreshing the token, the last response is kept.
+//
+//	var resp client.Response
+//	playlists, err := spotify.Playlist.UserPlaylistsContext(client.CaptureResponse(ctx, &resp), nil)
+//	log.Println(resp.StatusCode, resp.Get("X-Request-Id"))
+func CaptureResponse(ctx context.Context, resp *Response) context.Context {
+	return context.WithValue(ctx, responseCaptureKey{}, responseCapture{resp: resp})
+}
+
+// CaptureRawResponse is like CaptureResponse, but also keeps the raw body of the response.
+func CaptureRawResponse(ctx context.Context, resp *Response) context.Context {
+	return context.WithValue(ctx, responseCaptureKey{}, responseCapture{resp: resp, rawBody: true})
+}
+
+// captureResponse fills the Response of the request context, if any.
+// The body is buffered if the raw body is requested, so it can still be decoded afterwards.
+func captureResponse(req *http.Request, resp *http.Response) error {
+	capture, ok := req.Context().Value(responseCaptureKey{}).(responseCapture)
+	if !ok || capture.resp == nil {
+		return nil
+	}
+
+	limit, hasLimit := ParseRateLimit(resp.Header)
+	*capture.resp = Response{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		RateLimit:    limit,
+		HasRateLimit: hasLimit,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FromCache:    resp.Header.Get(FromCacheHeader) != "",
+	}
+
+	if capture.rawBody {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		capture.resp.Body = body
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+response_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/twitter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCaptureResponse checks that the status, the headers and the raw body are captured alongside the decoded
+// success or failure value.
+func TestCaptureResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		raw     bool
+		message string
+	}{
+		{"success", http.StatusOK, `{"message":"hello"}`, false, "hello"},
+		{"raw success", http.StatusOK, `{"message":"hello"}`, true, "hello"},
+		{"failure", http.StatusNotFound, `{"message":"not found"}`, false, "not found"},
+		{"raw failure", http.StatusNotFound, `{"message":"not found"}`, true, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Request-Id", "req-1")
+				w.Header().Set("ETag", `"v1"`)
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "59")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			capture := client.CaptureResponse
+			if tt.raw {
+				capture = client.CaptureRawResponse
+			}
+			var resp client.Response
+			cl := client.NewHttpClient().Base(server.URL)
+			req, err := cl.Get("/me").Request()
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(capture(context.Background(), &resp))
+
+			var success, failure struct {
+				Message string `json:"message"`
+			}
+			if _, err := cl.Do(req, &success, &failure); err != nil {
+				t.Fatal(err)
+			}
+			decoded := success.Message
+			if tt.status != http.StatusOK {
+				decoded = failure.Message
+			}
+			if decoded != tt.message {
+				t.Errorf("decoded message = %q, want %q", decoded, tt.message)
+			}
+
+			if resp.StatusCode != tt.status || resp.Get("X-Request-Id") != "req-1" || resp.ETag != `"v1"` {
+				t.Errorf("response = %d, %v, want %d with the headers", resp.StatusCode, resp.Header, tt.status)
+			}
+			if !resp.HasRateLimit || resp.RateLimit.Limit != 60 || resp.RateLimit.Remaining != 59 {
+				t.Errorf("rate limit = %+v, %v, want 59 of 60", resp.RateLimit, resp.HasRateLimit)
+			}
+			// The raw body is only kept if requested
+			if (tt.raw && string(resp.Body) != tt.body) || (!tt.raw && resp.Body != nil) {
+				t.Errorf("body = %q, want %q with raw body %v", resp.Body, tt.body, tt.raw)
+			}
+		})
+	}
+}
+
+// TestCaptureResponseProvider checks that the response of a provider request is captured together with the
+// decoded result or the mapped error.
+func TestCaptureResponseProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{"success", http.StatusOK, `{"ids":[1,2],"next_cursor":0}`, nil},
+		{"rate limit", http.StatusTooManyRequests, `{"errors":[{"code":88,"message":"Rate limit exceeded"}]}`, socialErrors.ErrRateLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Transaction-Id", "tx-1")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+			c := twitter.NewClient(context.Background(), cred, oauth1.NewToken("token", "secret"), client.WithBaseURL(server.URL))
+
+			var resp client.Response
+			ids, err := c.Follower.FollowerIDsContext(client.CaptureRawResponse(context.Background(), &resp), nil)
+			if tt.err == nil && (err != nil || len(ids.IDs) != 2) {
+				t.Errorf("FollowerIDs = %+v, %v, want 2 ids", ids, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+			if resp.StatusCode != tt.status || resp.Get("X-Transaction-Id") != "tx-1" || string(resp.Body) != tt.body {
+				t.Errorf("response = %d %v %q, want %d with the headers and body %q", resp.StatusCode, resp.Header, resp.Body, tt.status, tt.body)
+			}
+		})
+	}
+}
+
+// TestCaptureResponseRetry checks that the response of the last attempt is captured.
+func TestCaptureResponseRetry(t *testing.T) {
+	server := newFlakyServer(1, http.StatusServiceUnavailable, http.Header{"X-Failed": {"true"}})
+	defer server.Close()
+
+	cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithRetry(&client.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+	req, err := cl.Get("/me").Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp client.Response
+	if _, err := cl.Do(req.WithContext(client.CaptureRawResponse(context.Background(), &resp)), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Get("X-Failed") != "" || string(resp.Body) != `{}` {
+		t.Errorf("response = %d %v %q, want the one of the last attempt", resp.StatusCode, resp.Header, resp.Body)
+	}
+}
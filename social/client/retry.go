@@ -0,0 +1,242 @@
+/*
+retry.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried by HttpClient.Do.
+// Requests are retried if the request failed on the transport level, the API responded with
+// 429 (Too Many Requests) or any 5XX status code.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first request.
+	// Values lower than 2 disable retrying.
+	MaxAttempts int
+	// MinBackoff is the wait time before the first retry. It is doubled for every further retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. If the API requests a longer wait time with the
+	// Retry-After or X-RateLimit-Reset header, the request is not retried and the response is returned as is.
+	MaxBackoff time.Duration
+	// Jitter randomizes the backoff by up to the given fraction (0 to 1) to avoid thundering herds.
+	Jitter float64
+	// Methods are the HTTP methods which are retried. Since other methods might not be idempotent,
+	// only GET requests are retried if left empty.
+	Methods []string
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with 3 attempts, an exponential backoff starting
+// with 500ms up to 30s and a jitter of 20%. Only GET requests are retried.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+		Methods:     []string{http.MethodGet},
+	}
+}
+
+// WithRetry sets the RetryPolicy of the client. A nil policy disables retrying.
+func WithRetry(policy *RetryPolicy) Option {
+	return func(c *HttpClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// attempts returns the number of attempts allowed for the given request.
+// Requests with a body that cannot be rewound are only sent once.
+func (p *RetryPolicy) attempts(req *http.Request) int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 1
+	}
+
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	for _, m := range methods {
+		if m == req.Method {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+// shouldRetry reports whether the given response or error is worth retrying.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// Don't retry if the request was cancelled or its deadline exceeded
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns the wait time before the next attempt. If the API requested a wait time that
+// exceeds the MaxBackoff, false is returned and the request should not be retried.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := RetryAfter(resp); ok {
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+
+	wait := time.Duration(float64(p.MinBackoff) * math.Pow(2, float64(attempt-1)))
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait += time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	}
+	return wait, true
+}
+
+// RetryAfter returns the wait time requested by the API either by the Retry-After header
+// or on 429 by one of the rate limit reset headers.
+// See https://datatracker.ietf.org/doc/html/rfc7231#section-7.1.3
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(date)), true
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if reset, ok := resetTime(resp.Header); ok {
+		return nonNegative(time.Until(reset)), true
+	}
+	return 0, false
+}
+
+// resetHeaders are the headers used by the APIs to announce the reset of the current rate limit window.
+var resetHeaders = []string{
+	"X-Rate-Limit-Reset", // Twitter
+	"X-RateLimit-Reset",  // GitHub, Dribbble, Reddit
+	"Ratelimit-Reset",    // Twitch
+}
+
+// resetTime returns the reset time of the current rate limit window. Depending on the API the header
+// either holds a unix timestamp or the remaining seconds until the window resets.
+func resetTime(header http.Header) (time.Time, bool) {
+	for _, h := range resetHeaders {
+		v := header.Get(h)
+		if v == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			continue
+		}
+		// Anything before 2001 can't be a unix timestamp, so it must be the remaining seconds
+		if seconds < 1e9 {
+			return time.Now().Add(time.Duration(seconds * float64(time.Second))), true
+		}
+		return time.Unix(int64(seconds), 0), true
+	}
+	return time.Time{}, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// attemptsKey is the context key of the attempt counter of a request.
+type attemptsKey struct{}
+
+// abortError marks errors of the middlewares of the client, e.g. of the RequestSigner, which must not be retried.
+type abortError struct {
+	err error
+}
+
+func (e abortError) Error() string {
+	return e.err.Error()
+}
+
+// retry returns a Middleware sending the request again according to the RetryPolicy of the client.
+// Request bodies are rewound for every retry, so requests with a body must provide GetBody to be retried.
+// The number of attempts is counted in the counter set by send.
+func (c *HttpClient) retry(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		attempts := c.retryPolicy.attempts(req)
+		counter, _ := ctx.Value(attemptsKey{}).(*int)
+
+		for attempt := 1; ; attempt++ {
+			if counter != nil {
+				*counter = attempt
+			}
+			r := req
+			if attempt > 1 {
+				r = req.Clone(ctx)
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					r.Body = body
+				}
+			}
+
+			resp, err := next.Do(r)
+			if abort, ok := err.(abortError); ok {
+				return nil, abort.err
+			}
+			if attempt >= attempts || !c.retryPolicy.shouldRetry(ctx, resp, err) {
+				return resp, err
+			}
+			wait, ok := c.retryPolicy.backoff(attempt, resp)
+			if !ok {
+				return resp, err
+			}
+
+			if resp != nil {
+				io.Copy(ioutil.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+	})
+}
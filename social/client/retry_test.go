@@ -0,0 +1,264 @@
+/*
+retry_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers the first failures requests with the given status and headers and all further ones with 200.
+// The requests are recorded with the time they arrived and their body.
+type flakyServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	at     time.Time
+	body   string
+	header http.Header
+}
+
+func newFlakyServer(failures, status int, header http.Header) *flakyServer {
+	s := &flakyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, recordedRequest{at: time.Now(), body: string(body), header: r.Header.Clone()})
+		n := len(s.requests)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	return s
+}
+
+func (s *flakyServer) recorded() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]recordedRequest(nil), s.requests...)
+}
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		methods  []string
+		status   int
+		header   http.Header
+		attempts int
+		want     int
+	}{
+		{"server error", http.MethodGet, nil, http.StatusServiceUnavailable, nil, 3, http.StatusOK},
+		{"too many requests", http.MethodGet, nil, http.StatusTooManyRequests, nil, 3, http.StatusOK},
+		{"client error", http.MethodGet, nil, http.StatusNotFound, nil, 1, http.StatusNotFound},
+		{"post not retried by default", http.MethodPost, nil, http.StatusServiceUnavailable, nil, 1, http.StatusServiceUnavailable},
+		{"post retried if configured", http.MethodPost, []string{http.MethodPost}, http.StatusServiceUnavailable, nil, 3, http.StatusOK},
+		// The wait time requested by the API exceeds the MaxBackoff
+		{"retry-after above max", http.MethodGet, nil, http.StatusServiceUnavailable, http.Header{"Retry-After": {"3600"}}, 1, http.StatusServiceUnavailable},
+		{"rate limit reset above max", http.MethodGet, nil, http.StatusTooManyRequests, http.Header{"X-Ratelimit-Reset": {"3600"}}, 1, http.StatusTooManyRequests},
+		// X-RateLimit-Reset only applies to 429, so the exponential backoff is used
+		{"rate limit reset on server error", http.MethodGet, nil, http.StatusServiceUnavailable, http.Header{"X-Ratelimit-Reset": {"3600"}}, 3, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFlakyServer(2, tt.status, tt.header)
+			defer server.Close()
+
+			cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithRetry(&client.RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				MaxBackoff:  time.Second,
+				Methods:     tt.methods,
+			}))
+			builder := cl.Get("/me")
+			if tt.method == http.MethodPost {
+				builder = cl.Post("/me")
+			}
+			req, err := builder.Request()
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, _ := cl.Do(req, nil, nil)
+			if resp == nil || resp.StatusCode != tt.want {
+				t.Errorf("response = %v, want status %d", resp, tt.want)
+			}
+			if n := len(server.recorded()); n != tt.attempts {
+				t.Errorf("sent %d requests, want %d", n, tt.attempts)
+			}
+		})
+	}
+}
+
+// TestRetryBackoff checks the exponential backoff and that Retry-After replaces it.
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		min    []time.Duration
+	}{
+		{"exponential", nil, []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond}},
+		{"retry-after", http.Header{"Retry-After": {"1"}}, []time.Duration{time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFlakyServer(len(tt.min), http.StatusServiceUnavailable, tt.header)
+			defer server.Close()
+
+			cl := client.NewHttpClient(client.WithBaseURL(server.URL), client.WithRetry(&client.RetryPolicy{
+				MaxAttempts: len(tt.min) + 1,
+				MinBackoff:  20 * time.Millisecond,
+				MaxBackoff:  5 * time.Second,
+			}))
+			req, err := cl.Get("/me").Request()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cl.Do(req, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			requests := server.recorded()
+			if len(requests) != len(tt.min)+1 {
+				t.Fatalf("sent %d requests, want %d", len(requests), len(tt.min)+1)
+			}
+			for i, min := range tt.min {
+				// Retry-After has a resolution of seconds, so the upper bound is generous
+				if gap := requests[i+1].at.Sub(requests[i].at); gap < min || gap > 2*min+500*time.Millisecond {
+					t.Errorf("wait before attempt %d = %v, want about %v", i+2, gap, min)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{"seconds", http.StatusServiceUnavailable, http.Header{"Retry-After": {"120"}}, 120 * time.Second, true},
+		{"http date", http.StatusServiceUnavailable, http.Header{"Retry-After": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}, time.Minute, true},
+		{"date in the past", http.StatusServiceUnavailable, http.Header{"Retry-After": {"Sat, 17 Oct 2026 10:00:00 GMT"}}, 0, true},
+		{"remaining seconds on 429", http.StatusTooManyRequests, http.Header{"X-Ratelimit-Reset": {"30"}}, 30 * time.Second, true},
+		{"unix timestamp on 429", http.StatusTooManyRequests, http.Header{"X-Rate-Limit-Reset": {strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)}}, time.Minute, true},
+		{"reset on 503", http.StatusServiceUnavailable, http.Header{"X-Ratelimit-Reset": {"30"}}, 0, false},
+		{"no header", http.StatusTooManyRequests, http.Header{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := client.RetryAfter(&http.Response{StatusCode: tt.status, Header: tt.header})
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			// Dates have a resolution of seconds
+			if d := got - tt.want; d > time.Second || d < -time.Second {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRetryBody checks that a request body is rewound with GetBody for every attempt and that a request
+// with a body that cannot be rewound is only sent once.
+func TestRetryBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		getBody  bool
+		attempts int
+	}{
+		{"with GetBody", true, 3},
+		{"without GetBody", false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFlakyServer(2, http.StatusServiceUnavailable, nil)
+			defer server.Close()
+
+			cl := client.NewHttpClient(client.WithRetry(&client.RetryPolicy{
+				MaxAttempts: 3,
+				MinBackoff:  time.Millisecond,
+				Methods:     []string{http.MethodPost},
+			}))
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader("status=hello"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.getBody {
+				req.Body = ioutil.NopCloser(strings.NewReader("status=hello"))
+				req.GetBody = nil
+			}
+			cl.Do(req, nil, nil)
+
+			requests := server.recorded()
+			if len(requests) != tt.attempts {
+				t.Fatalf("sent %d requests, want %d", len(requests), tt.attempts)
+			}
+			for i, r := range requests {
+				if r.body != "status=hello" {
+					t.Errorf("body of attempt %d = %q, want the full body", i+1, r.body)
+				}
+			}
+		})
+	}
+}
+
+// TestRetrySignsEveryAttempt checks that every attempt gets a new OAuth1 signature, so replayed nonces are not rejected.
+func TestRetrySignsEveryAttempt(t *testing.T) {
+	server := newFlakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	a := oauth1.NewOAuth(context.Background(), cred, oauth1.NewToken("token", "secret"), client.NewHttpClient())
+	cl := client.NewHttpClient(
+		client.WithBaseURL(server.URL),
+		client.WithRetry(&client.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}),
+	).Sign(a.SignRequest)
+
+	req, err := cl.Get("/me").Request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cl.Do(req, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range server.recorded() {
+		auth := r.header.Get(oauth1.AuthorizationHeaderName)
+		if !strings.Contains(auth, "oauth_signature=") || seen[auth] {
+			t.Errorf("attempt %d: Authorization = %q, want a new signature", i+1, auth)
+		}
+		seen[auth] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("got %d signatures, want 3", len(seen))
+	}
+}
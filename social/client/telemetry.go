@@ -0,0 +1,195 @@
+/*
+telemetry.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricNoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	traceNoop "go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"time"
+)
+
+// instrumentationName is the name of the tracer and meter.
+const instrumentationName = "github.com/emrearmagan/go-social/social/client"
+
+// Attribute keys of the spans and metrics.
+const (
+	AttributeProvider   = attribute.Key("go_social.provider")
+	AttributeEndpoint   = attribute.Key("go_social.endpoint")
+	AttributeRetryCount = attribute.Key("go_social.retry_count")
+	AttributeErrorClass = attribute.Key("go_social.error_class")
+	AttributeMethod     = attribute.Key("http.request.method")
+	AttributeStatusCode = attribute.Key("http.response.status_code")
+)
+
+// telemetry holds the OpenTelemetry instruments of a client. The instruments are no-ops unless
+// a TracerProvider or MeterProvider is set.
+type telemetry struct {
+	tracer    trace.Tracer
+	duration  metric.Float64Histogram
+	errors    metric.Int64Counter
+	remaining metric.Int64Gauge
+}
+
+// newTelemetry returns telemetry with no-op instruments.
+func newTelemetry() *telemetry {
+	t := &telemetry{tracer: traceNoop.NewTracerProvider().Tracer(instrumentationName)}
+	t.meter(metricNoop.NewMeterProvider())
+	return t
+}
+
+// meter creates the metric instruments with the given MeterProvider.
+// Instruments which cannot be created fall back to no-ops.
+func (t *telemetry) meter(mp metric.MeterProvider) {
+	noop := metricNoop.NewMeterProvider().Meter(instrumentationName)
+	m := mp.Meter(instrumentationName)
+
+	var err error
+	if t.duration, err = m.Float64Histogram("go_social.request.duration",
+		metric.WithDescription("Duration of the API calls including retries."),
+		metric.WithUnit("s"),
+	); err != nil {
+		t.duration, _ = noop.Float64Histogram("go_social.request.duration")
+	}
+	if t.errors, err = m.Int64Counter("go_social.request.errors",
+		metric.WithDescription("Failed API calls by error class."),
+		metric.WithUnit("{error}"),
+	); err != nil {
+		t.errors, _ = noop.Int64Counter("go_social.request.errors")
+	}
+	if t.remaining, err = m.Int64Gauge("go_social.ratelimit.remaining",
+		metric.WithDescription("Remaining requests of the current rate limit window."),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		t.remaining, _ = noop.Int64Gauge("go_social.ratelimit.remaining")
+	}
+}
+
+// WithTracerProvider records a span for every API call with the given TracerProvider.
+// The spans carry the provider, endpoint, method, status code and retry count.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *HttpClient) {
+		if tp == nil {
+			return
+		}
+		t := *c.telemetry
+		t.tracer = tp.Tracer(instrumentationName)
+		c.telemetry = &t
+	}
+}
+
+// WithMeterProvider records the latency, error classes and remaining rate limit of the API calls with the given MeterProvider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *HttpClient) {
+		if mp == nil {
+			return
+		}
+		t := *c.telemetry
+		t.meter(mp)
+		c.telemetry = &t
+	}
+}
+
+// attributes returns the attributes identifying the endpoint of the request.
+func (c *HttpClient) attributes(req *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttributeProvider.String(c.provider),
+		AttributeEndpoint.String(c.endpointOf(req)),
+		AttributeMethod.String(req.Method),
+	}
+}
+
+// startSpan starts the span of the API call. The returned request carries the span in its context.
+func (c *HttpClient) startSpan(req *http.Request) (*http.Request, trace.Span) {
+	name := c.provider + " " + c.endpointOf(req)
+	ctx, span := c.telemetry.tracer.Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(c.attributes(req)...),
+	)
+	return req.WithContext(ctx), span
+}
+
+// endSpan records the result of the API call and ends the span.
+func (c *HttpClient) endSpan(req *http.Request, span trace.Span, resp *http.Response, attempts int, latency time.Duration, err error) {
+	ctx := req.Context()
+	attrs := c.attributes(req)
+
+	if resp != nil {
+		attrs = append(attrs, AttributeStatusCode.Int(resp.StatusCode))
+		if limit, ok := ParseRateLimit(resp.Header); ok {
+			c.telemetry.remaining.Record(ctx, int64(limit.Remaining), metric.WithAttributes(attrs[:2]...))
+		}
+	}
+	c.telemetry.duration.Record(ctx, latency.Seconds(), metric.WithAttributes(attrs...))
+
+	span.SetAttributes(AttributeRetryCount.Int(attempts - 1))
+	if resp != nil {
+		span.SetAttributes(AttributeStatusCode.Int(resp.StatusCode))
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case resp != nil && resp.StatusCode >= 400:
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	span.End()
+}
+
+// RecordError counts the given error by its class, e.g. "rate_limit" for an errors.ErrRateLimit.
+// It is called by the authers with the mapped error of the API call. Nil errors are ignored.
+func (c *HttpClient) RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	c.telemetry.errors.Add(ctx, 1, metric.WithAttributes(
+		AttributeProvider.String(c.provider),
+		AttributeEndpoint.String(Redact(c.endpoint)),
+		AttributeErrorClass.String(ErrorClass(err)),
+	))
+}
+
+// errorClasses maps the errors of models/errors to their class.
+var errorClasses = map[error]string{
+	socialErrors.ErrBadRequest:            "bad_request",
+	socialErrors.ErrNotFound:              "not_found",
+	socialErrors.ErrUnauthorized:          "unauthorized",
+	socialErrors.ErrRateLimit:             "rate_limit",
+	socialErrors.ErrBadAuthenticationData: "bad_authentication_data",
+	socialErrors.ErrInvalidOrExpiredToken: "invalid_or_expired_token",
+	socialErrors.ErrForbidden:             "forbidden",
+	socialErrors.ErrNotModified:           "not_modified",
+	socialErrors.ErrApiError:              "api_error",
+	socialErrors.ErrUnknownError:          "unknown",
+}
+
+// ErrorClass returns the class of the given error, which is used as metric attribute.
+// Errors of models/errors are classified by their kind, e.g. "rate_limit". Other errors are
+// classified as "canceled", "timeout" or "transport".
+func ErrorClass(err error) string {
+	var socialErr socialErrors.SocialError
+	if errors.As(err, &socialErr) {
+		if class, ok := errorClasses[socialErr.Errors]; ok {
+			return class
+		}
+		return "unknown"
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "transport"
+}
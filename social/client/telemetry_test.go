@@ -0,0 +1,158 @@
+/*
+telemetry_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package client_test
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/twitter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTelemetry sends a successful request and a request failing after a retry through a Twitter client
+// and checks the recorded spans and metrics.
+func TestTelemetry(t *testing.T) {
+	var followerCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case twitter.UserPath:
+			w.Header().Set("X-Rate-Limit-Limit", "75")
+			w.Header().Set("X-Rate-Limit-Remaining", "74")
+			w.Write([]byte(`{"id":1,"screen_name":"go_social"}`))
+		case twitter.FollowerIdsPath:
+			if atomic.AddInt32(&followerCalls, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"errors":[{"code":131,"message":"Internal error"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errors":[{"code":88,"message":"Rate limit exceeded"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := twitter.NewClient(context.Background(), cred, oauth1.NewToken("token", "secret"),
+		client.WithBaseURL(server.URL),
+		client.WithTracerProvider(tp),
+		client.WithMeterProvider(mp),
+		client.WithRetry(&client.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	)
+
+	if _, err := c.User.UserCredentialsContext(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Follower.FollowerIDsContext(context.Background(), nil); err == nil {
+		t.Fatal("expected a rate limit error")
+	}
+
+	// Spans
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	tests := []struct {
+		span     tracetest.SpanStub
+		name     string
+		endpoint string
+		status   int
+		retries  int
+		code     codes.Code
+	}{
+		{spans[0], "twitter " + twitter.UserPath, twitter.UserPath, 200, 0, codes.Unset},
+		{spans[1], "twitter " + twitter.FollowerIdsPath, twitter.FollowerIdsPath, 429, 1, codes.Error},
+	}
+	for _, tt := range tests {
+		if tt.span.Name != tt.name {
+			t.Errorf("span name = %q, want %q", tt.span.Name, tt.name)
+		}
+		if tt.span.SpanKind != trace.SpanKindClient {
+			t.Errorf("%s: span kind = %v, want client", tt.name, tt.span.SpanKind)
+		}
+		if tt.span.Status.Code != tt.code {
+			t.Errorf("%s: status = %v, want %v", tt.name, tt.span.Status.Code, tt.code)
+		}
+		attrs := attribute.NewSet(tt.span.Attributes...)
+		want := []attribute.KeyValue{
+			client.AttributeProvider.String(twitter.Name),
+			client.AttributeEndpoint.String(tt.endpoint),
+			client.AttributeMethod.String(http.MethodGet),
+			client.AttributeStatusCode.Int(tt.status),
+			client.AttributeRetryCount.Int(tt.retries),
+		}
+		for _, kv := range want {
+			if v, ok := attrs.Value(kv.Key); !ok || v != kv.Value {
+				t.Errorf("%s: attribute %s = %v, want %v", tt.name, kv.Key, v.Emit(), kv.Value.Emit())
+			}
+		}
+	}
+
+	// Metrics
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	duration, ok := metrics["go_social.request.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("missing duration histogram, got %v", metrics)
+	}
+	counts := make(map[string]uint64)
+	for _, dp := range duration.DataPoints {
+		endpoint, _ := dp.Attributes.Value(client.AttributeEndpoint)
+		counts[endpoint.AsString()] += dp.Count
+		if dp.Sum <= 0 {
+			t.Errorf("%s: duration sum = %v, want > 0", endpoint.AsString(), dp.Sum)
+		}
+	}
+	if counts[twitter.UserPath] != 1 || counts[twitter.FollowerIdsPath] != 1 {
+		t.Errorf("duration counts = %v, want one call per endpoint", counts)
+	}
+
+	errorCount, ok := metrics["go_social.request.errors"].(metricdata.Sum[int64])
+	if !ok || len(errorCount.DataPoints) != 1 {
+		t.Fatalf("errors = %+v, want a single data point", metrics["go_social.request.errors"])
+	}
+	dp := errorCount.DataPoints[0]
+	class, _ := dp.Attributes.Value(client.AttributeErrorClass)
+	endpoint, _ := dp.Attributes.Value(client.AttributeEndpoint)
+	if dp.Value != 1 || class.AsString() != "rate_limit" || endpoint.AsString() != twitter.FollowerIdsPath {
+		t.Errorf("errors = %d %s %s, want 1 rate_limit %s", dp.Value, class.AsString(), endpoint.AsString(), twitter.FollowerIdsPath)
+	}
+
+	remaining, ok := metrics["go_social.ratelimit.remaining"].(metricdata.Gauge[int64])
+	if !ok || len(remaining.DataPoints) != 1 || remaining.DataPoints[0].Value != 74 {
+		t.Errorf("remaining = %+v, want 74", metrics["go_social.ratelimit.remaining"])
+	}
+}
@@ -13,39 +13,65 @@ import (
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social/client"
 	"strconv"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "dribbble"
+
 	Base = "https://api.dribbble.com/"
 )
 
+// Endpoint is Dribbble's OAuth2 endpoint.
+// https://developer.dribbble.com/v2/oauth/
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://dribbble.com/oauth/authorize",
+	TokenURL:  "https://dribbble.com/oauth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 type Client struct {
+	oauth2 *oauth2.OAuth2
+
 	User  *UserService
 	Shots *ShotService
 }
 
 // NewClient returns a new Dribbble Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token) *Client {
-	cl := client.NewHttpClient().Base(Base)
-	auther := oauth2.NewOAuth(ctx, c, token, cl)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
 	return &Client{
-		User:  newUserService(auther),
-		Shots: newShotService(auther),
+		oauth2: auther,
+		User:   newUserService(auther),
+		Shots:  newShotService(auther),
 	}
 }
 
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (d *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return d.oauth2.RateLimit(endpoint)
+}
+
 func (d *Client) GoSocialUser() (*models.SocialUser, error) {
-	s, err := d.Shots.DribbbleShots()
+	return d.GoSocialUserContext(d.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (d *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	s, err := d.Shots.DribbbleShotsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := d.User.UserCredentials()
+	u, err := d.User.UserCredentialsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	goSocial := models.SocialUser{
+		Provider:     Name,
 		Username:     u.Login,
 		Name:         u.Name,
 		UserId:       strconv.Itoa(u.ID),
@@ -54,6 +80,10 @@ func (d *Client) GoSocialUser() (*models.SocialUser, error) {
 		AvatarUrl:    u.AvatarURL,
 		Followers:    u.FollowersCount,
 		Url:          u.HTMLURL,
+		Bio:          u.Bio,
+		Location:     u.Location,
+		CreatedAt:    models.OptionalTime(u.CreatedAt),
+		FetchedAt:    time.Now(),
 	}
 
 	return &goSocial, nil
@@ -0,0 +1,33 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package dribbble
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "bad credentials", Status: 401, ContentType: "application/json", Body: `{"message":"Bad credentials."}`, Kind: socialErrors.ErrUnauthorized},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"message":"You are not permitted to access this resource."}`, Kind: socialErrors.ErrForbidden},
+		{Name: "not found", Status: 404, ContentType: "application/json", Body: `{"message":"Not found."}`, Kind: socialErrors.ErrNotFound},
+		{Name: "rate limit", Status: 429, ContentType: "application/json", Body: `{"message":"API rate limit exceeded."}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Retryable: true},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background())
+		return err
+	})
+}
@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package dribbble
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"time"
@@ -31,10 +32,15 @@ func newShotService(auther *oauth2.OAuth2) *ShotService {
 // DribbbleShots returns all shots for the authenticated user.
 // See: https://developer.dribbble.com/v2/shots/#list-shots for more information
 func (s *ShotService) DribbbleShots() (*Shots, error) {
+	return s.DribbbleShotsContext(s.oauth2.Context())
+}
+
+// DribbbleShotsContext is like DribbbleShots, but sends the request with the given context.
+func (s *ShotService) DribbbleShotsContext(ctx context.Context) (*Shots, error) {
 	shots := new(Shots)
 	apiError := new(APIError)
 
-	err := s.oauth2.Get(ShotsPath, shots, apiError, nil)
+	err := s.oauth2.WithContext(ctx).Get(ShotsPath, shots, apiError, nil)
 
 	return shots, social.CheckError(err)
 }
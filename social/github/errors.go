@@ -9,6 +9,7 @@ package github
 import (
 	"fmt"
 	"github.com/emrearmagan/go-social/models/errors"
+	"time"
 )
 
 // APIError represents a GitHub API error with its corresponding http StatusCode response
@@ -16,6 +17,10 @@ import (
 type APIError struct {
 	StatusCode int
 	Errors     ErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 // ErrorDetail represents the actual error response from the Api
@@ -32,37 +37,51 @@ func (e *APIError) Error() string {
 	if len(e.Errors.Message) > 0 {
 		return fmt.Sprintf("github: %d - %v -%v", e.StatusCode, e.Errors, e.Errors.Description)
 	}
+	if e.Body != "" {
+		return fmt.Sprintf("github: %d - %v", e.StatusCode, e.Body)
+	}
 	return ""
 }
 
 // Empty returns true if empty. Otherwise, at least 1 error message/code is
 // present and false is returned.
 func (e *APIError) Empty() bool {
-	if len(e.Errors.Message) == 0 {
-		return true
-	}
-	return false
+	return len(e.Errors.Message) == 0 && e.Body == ""
 }
 
 func (e *APIError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *APIError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *APIError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 func (e *APIError) Status() int {
 	return e.StatusCode
 }
 
 func (e *APIError) ReturnErrorResponse() error {
-	switch e.Status() {
-	case 304: // The content has not been modified and client should use cached data
-		return errors.New(errors.ErrNotModified, e.Error())
-	case 401, 403: // Invalid or expired token or client is not permitted to perform this action.
-		return errors.New(errors.ErrUnauthorized, e.Error())
-	case 429: // Rate limit exceeded	 - The request limit for this resource has been reached for the current rate limit window.
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case 500, 502, 503: //Internal api error
-		return errors.New(errors.ErrApiError, e.Error())
-	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
 
-	return errors.New(errors.ErrUnknownError, e.Error())
+// socialError maps the APIError onto a SocialError of the given kind.
+func (e *APIError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       "",
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
+	}
 }
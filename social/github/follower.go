@@ -7,8 +7,10 @@ Copyright © go-social. All rights reserved.
 package github
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
+	"strconv"
 )
 
 const (
@@ -30,6 +32,11 @@ func newFollowerService(oauth2 *oauth2.OAuth2) *FollowerService {
 // FollowerIds returns the ids of the follower for the authenticated user.
 // https://docs.github.com/en/rest/reference/users#list-followers-of-the-authenticated-user
 func (f *FollowerService) FollowerIds(cursor int64, max *int) (*FollowersIdResponse, error) {
+	return f.FollowerIdsContext(f.oauth2.Context(), cursor, max)
+}
+
+// FollowerIdsContext is like FollowerIds, but sends the request with the given context.
+func (f *FollowerService) FollowerIdsContext(ctx context.Context, cursor int64, max *int) (*FollowersIdResponse, error) {
 	followers := new(FollowersIdResponse)
 	apiError := new(APIError)
 
@@ -38,10 +45,57 @@ func (f *FollowerService) FollowerIds(cursor int64, max *int) (*FollowersIdRespo
 		Page:    int(cursor),
 	}
 
-	err := f.oauth2.Get(FollowerPath, followers, apiError, params)
+	err := f.oauth2.WithContext(ctx).Get(FollowerPath, followers, apiError, params)
 	return followers, social.CheckError(err)
 }
 
+// FollowerIdsIterator returns an Iterator over the ids of all followers of the authenticated user.
+// max is the number of results per page.
+func (f *FollowerService) FollowerIdsIterator(max *int) *social.Iterator[int64] {
+	return social.NewIterator(idPager(UserFollowerIdParams{PerPage: max}, func(ctx context.Context, params *UserFollowerIdParams) (*FollowersIdResponse, error) {
+		return f.FollowerIdsContext(ctx, int64(params.Page), params.PerPage)
+	}))
+}
+
+// idPager returns a Pager for the paged id endpoints. The cursor is the page number
+// and the last page is reached once a page returns fewer ids than requested.
+func idPager(params UserFollowerIdParams, fetch func(context.Context, *UserFollowerIdParams) (*FollowersIdResponse, error)) social.Pager[int64] {
+	return func(ctx context.Context, cursor string) ([]int64, string, error) {
+		page := 1
+		if params.Page > 0 {
+			page = params.Page
+		}
+		if cursor != "" {
+			p, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			page = p
+		}
+
+		p := params
+		p.Page = page
+		resp, err := fetch(ctx, &p)
+		if err != nil {
+			return nil, "", err
+		}
+
+		ids := make([]int64, len(*resp))
+		for i, f := range *resp {
+			ids[i] = f.Id
+		}
+
+		perPage := 30 // default of the API
+		if params.PerPage != nil {
+			perPage = *params.PerPage
+		}
+		if len(ids) == 0 || len(ids) < perPage {
+			return ids, "", nil
+		}
+		return ids, strconv.Itoa(page + 1), nil
+	}
+}
+
 // UserFollowerIdParams are the parameters for FollowerIds and FollowingIds
 type UserFollowerIdParams struct {
 	PerPage *int `url:"per_page,omitempty"` // PerPage per page (max 100), Default: 30
@@ -0,0 +1,114 @@
+/*
+follower_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pagedServer serves the given ids on pages of per_page ids. The requested pages are appended to the given slice.
+func pagedServer(path string, ids []int64, pages *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		*pages = append(*pages, r.URL.Query().Get("page"))
+
+		var items []string
+		for i := (page - 1) * perPage; i >= 0 && i < len(ids) && i < page*perPage; i++ {
+			items = append(items, fmt.Sprintf(`{"id":%d}`, ids[i]))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+}
+
+// TestIdsIterator checks that the iterators request the following page until a page has fewer ids than requested.
+func TestIdsIterator(t *testing.T) {
+	perPage := 2
+	tests := []struct {
+		name  string
+		path  string
+		ids   []int64
+		fetch func(c *Client) ([]int64, error)
+		want  []int64
+		pages []string
+	}{
+		{"followers", FollowerPath, []int64{1, 2, 3}, func(c *Client) ([]int64, error) {
+			return c.Follower.FollowerIdsIterator(&perPage).All(context.Background())
+		}, []int64{1, 2, 3}, []string{"1", "2"}},
+		{"followers full last page", FollowerPath, []int64{1, 2, 3, 4}, func(c *Client) ([]int64, error) {
+			return c.Follower.FollowerIdsIterator(&perPage).All(context.Background())
+		}, []int64{1, 2, 3, 4}, []string{"1", "2", "3"}},
+		{"following starting page", FollowingPath, []int64{1, 2, 3, 4, 5}, func(c *Client) ([]int64, error) {
+			return c.Following.FollowingIdsIterator(&UserFollowerIdParams{PerPage: &perPage, Page: 2}).All(context.Background())
+		}, []int64{3, 4, 5}, []string{"2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []string
+			server := pagedServer(tt.path, tt.ids, &pages)
+			defer server.Close()
+
+			cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+			c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), nil, client.WithBaseURL(server.URL))
+			ids, err := tt.fetch(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ids = %v, want %v", ids, tt.want)
+			}
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Errorf("requested pages %q, want %q", pages, tt.pages)
+			}
+		})
+	}
+}
+
+// TestServiceContext checks that the ...Context methods send the request with the given context and that the
+// other methods fall back to the context of NewClient.
+func TestServiceContext(t *testing.T) {
+	var pages []string
+	server := pagedServer(FollowerPath, []int64{1, 2}, &pages)
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	perPage := 2
+
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), nil, client.WithBaseURL(server.URL))
+	if _, err := c.Follower.FollowerIdsContext(canceled, 1, &perPage); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with a canceled call context = %v, want %v", err, context.Canceled)
+	}
+	if _, err := c.Follower.FollowerIds(1, &perPage); err != nil {
+		t.Errorf("error with the context of NewClient = %v, want none", err)
+	}
+
+	c = NewClient(canceled, cred, oauth2.NewToken("token", ""), nil, client.WithBaseURL(server.URL))
+	if followers, err := c.Follower.FollowerIdsContext(context.Background(), 1, &perPage); err != nil || len(*followers) != 2 {
+		t.Errorf("FollowerIdsContext = %v, %v, want 2 followers despite the canceled context of NewClient", followers, err)
+	}
+	if _, err := c.Follower.FollowerIds(1, &perPage); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with the canceled context of NewClient = %v, want %v", err, context.Canceled)
+	}
+}
@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package github
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 )
@@ -30,9 +31,24 @@ func newFollowingService(oauth2 *oauth2.OAuth2) *FollowingService {
 // FollowingIds returns the ids of the following for the authenticated user.
 // https://docs.github.com/en/rest/reference/users#list-the-people-the-authenticated-user-follows
 func (f *FollowingService) FollowingIds(params *UserFollowerIdParams) (*FollowersIdResponse, error) {
+	return f.FollowingIdsContext(f.oauth2.Context(), params)
+}
+
+// FollowingIdsContext is like FollowingIds, but sends the request with the given context.
+func (f *FollowingService) FollowingIdsContext(ctx context.Context, params *UserFollowerIdParams) (*FollowersIdResponse, error) {
 	following := new(FollowersIdResponse)
 	apiError := new(APIError)
 
-	err := f.oauth2.Get(FollowingPath, following, apiError, params)
+	err := f.oauth2.WithContext(ctx).Get(FollowingPath, following, apiError, params)
 	return following, social.CheckError(err)
 }
+
+// FollowingIdsIterator returns an Iterator over the ids of all users the authenticated user follows.
+// The Page of the given params is used as the starting point.
+func (f *FollowingService) FollowingIdsIterator(params *UserFollowerIdParams) *social.Iterator[int64] {
+	p := UserFollowerIdParams{}
+	if params != nil {
+		p = *params
+	}
+	return social.NewIterator(idPager(p, f.FollowingIdsContext))
+}
@@ -15,13 +15,27 @@ import (
 	"github.com/emrearmagan/go-social/social/client"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "github"
+
 	Base = "https://api.github.com/"
 )
 
+// Endpoint is GitHub's OAuth2 endpoint.
+// https://docs.github.com/en/developers/apps/building-oauth-apps/authorizing-oauth-apps#web-application-flow
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://github.com/login/oauth/authorize",
+	TokenURL:  "https://github.com/login/oauth/access_token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 type Client struct {
+	oauth2 *oauth2.OAuth2
+
 	User      *UserService
 	Follower  *FollowerService
 	Following *FollowingService
@@ -33,26 +47,37 @@ type Client struct {
 // All API requests MUST include a valid User-Agent header. Requests with no User-Agent header will be rejected.
 // It is requested to use the username or the application name
 // See for more: https://docs.github.com/en/rest/overview/resources-in-the-rest-api#user-agent-required
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, useragent *string) *Client {
-	cl := client.NewHttpClient().Base(Base)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, useragent *string, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
 	if useragent != nil {
-		cl.Add("User-Agent", *useragent)
+		cl = cl.Use(client.SetHeader("User-Agent", *useragent))
 	}
 
-	auther := oauth2.NewOAuth(ctx, c, token, cl).Signer(GithubSigner{
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint).Signer(GithubSigner{
 		ConsumerKey:    c.ConsumerKey,
 		ConsumerSecret: c.ConsumerSecret,
 	})
 	//oauth.AuthorizationPrefix = AuthorizationPrefix //TODO: Need different authorization header
 	return &Client{
+		oauth2:    auther,
 		User:      newUserService(auther),
 		Follower:  newFollowerService(auther),
 		Following: newFollowingService(auther),
 	}
 }
 
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (g *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return g.oauth2.RateLimit(endpoint)
+}
+
 func (g *Client) GoSocialUser() (*models.SocialUser, error) {
-	u, err := g.User.UserCredentials()
+	return g.GoSocialUserContext(g.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (g *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	u, err := g.User.UserCredentialsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +85,7 @@ func (g *Client) GoSocialUser() (*models.SocialUser, error) {
 	pro := strings.ToLower(u.Plan.Name) == "pro"
 	repos := u.PublicRepos + u.TotalPrivateRepos
 	goSocial := models.SocialUser{
+		Provider:     Name,
 		Username:     u.Login,
 		Name:         u.Name,
 		UserId:       strconv.Itoa(u.ID),
@@ -69,6 +95,11 @@ func (g *Client) GoSocialUser() (*models.SocialUser, error) {
 		Followers:    u.Followers,
 		Following:    &u.Following,
 		Url:          u.HTMLURL,
+		Bio:          u.Bio,
+		Location:     u.Location,
+		Email:        u.Email,
+		CreatedAt:    models.OptionalTime(u.CreatedAt),
+		FetchedAt:    time.Now(),
 	}
 
 	return &goSocial, nil
@@ -0,0 +1,70 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package github
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social"
+	"strconv"
+)
+
+// Client implements social.Provider.
+var _ social.Provider = (*Client)(nil)
+
+func init() {
+	social.Register(Name, newProvider)
+}
+
+// newProvider builds a Client for the account. The token of the account must be a *oauth2.Token.
+// The UserAgent of the account is sent as User-Agent header if set.
+func newProvider(ctx context.Context, account social.Account) (social.Provider, error) {
+	token, ok := account.Token.(*oauth2.Token)
+	if !ok {
+		return nil, social.TokenTypeError(Name, account.Token)
+	}
+	var userAgent *string
+	if account.UserAgent != "" {
+		userAgent = &account.UserAgent
+	}
+	return NewClient(ctx, &account.Credentials, token, userAgent, account.Options...), nil
+}
+
+// Name returns the provider name.
+func (g *Client) Name() string {
+	return Name
+}
+
+// Capabilities returns the supported methods of the Provider. GitHub tokens do not expire and are revoked by the user.
+func (g *Client) Capabilities() social.Capabilities {
+	return social.CapFollowers | social.CapFollowing
+}
+
+// FollowersIterator returns an Iterator over the ids of the followers of the authenticated user.
+func (g *Client) FollowersIterator() *social.Iterator[string] {
+	return social.MapIterator(g.Follower.FollowerIdsIterator(nil), formatID)
+}
+
+// FollowingIterator returns an Iterator over the ids of the users the authenticated user follows.
+func (g *Client) FollowingIterator() *social.Iterator[string] {
+	return social.MapIterator(g.Following.FollowingIdsIterator(nil), formatID)
+}
+
+// RefreshContext returns social.ErrUnsupported.
+func (g *Client) RefreshContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
+
+// RevokeContext returns social.ErrUnsupported.
+func (g *Client) RevokeContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
+
+// formatID formats a numeric user id.
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
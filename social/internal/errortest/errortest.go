@@ -0,0 +1,83 @@
+/*
+errortest.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+// Package errortest provides the shared setup of the error mapping tests of the provider packages.
+package errortest
+
+import (
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Case is an error response of a provider API and the SocialError it is expected to be mapped to.
+type Case struct {
+	Name        string
+	Status      int
+	ContentType string
+	Body        string
+	// RetryAfter is sent as Retry-After header in seconds, if set
+	RetryAfter string
+	Kind       error
+	Code       string
+	Retryable  bool
+}
+
+// Run serves the response of every case with an httptest.Server and checks the error returned by call, which
+// sends a request with a client of the provider pointed to the given base url. E is the error type of the provider.
+// Exactly one request must reach the server, i.e. a rejected token must not be refreshed.
+func Run[E error](t *testing.T, provider string, cases []Case, call func(baseURL string) error) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.Header().Set("Content-Type", tt.ContentType)
+				if tt.RetryAfter != "" {
+					w.Header().Set("Retry-After", tt.RetryAfter)
+				}
+				w.WriteHeader(tt.Status)
+				w.Write([]byte(tt.Body))
+			}))
+			defer server.Close()
+
+			err := call(server.URL)
+			if n := atomic.LoadInt32(&requests); n != 1 {
+				t.Errorf("sent %d requests, want 1", n)
+			}
+
+			if !errors.Is(err, tt.Kind) {
+				t.Fatalf("error = %v, want kind %v", err, tt.Kind)
+			}
+			var socialErr socialErrors.SocialError
+			if !errors.As(err, &socialErr) {
+				t.Fatalf("error %T is no SocialError", err)
+			}
+			if socialErr.Provider != provider || socialErr.StatusCode != tt.Status || socialErr.Code != tt.Code {
+				t.Errorf("provider, status, code = %q, %d, %q, want %q, %d, %q", socialErr.Provider, socialErr.StatusCode, socialErr.Code, provider, tt.Status, tt.Code)
+			}
+			if got := socialErrors.IsRetryable(err); got != tt.Retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.Retryable)
+			}
+			if tt.RetryAfter != "" {
+				seconds, _ := strconv.Atoi(tt.RetryAfter)
+				if want := time.Duration(seconds) * time.Second; socialErr.RetryAfter != want {
+					t.Errorf("RetryAfter = %v, want %v", socialErr.RetryAfter, want)
+				}
+			}
+			var apiErr E
+			if !errors.As(err, &apiErr) {
+				t.Errorf("errors.As(err, %T) = false", apiErr)
+			}
+		})
+	}
+}
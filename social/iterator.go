@@ -0,0 +1,95 @@
+/*
+iterator.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package social
+
+import (
+	"context"
+	"errors"
+)
+
+// Done is returned by Iterator.Next when there are no more pages.
+var Done = errors.New("social: no more pages")
+
+// Pager fetches the page for the given cursor of a paginated endpoint. It returns the items of the page
+// and the cursor of the next page. An empty cursor requests the first page, an empty next cursor
+// indicates the last page.
+type Pager[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+// Iterator iterates page by page over the items of a paginated endpoint.
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	pager  Pager[T]
+	cursor string
+	done   bool
+	// maximum number of items to return. Zero means no limit.
+	max   int
+	count int
+}
+
+// NewIterator returns a new Iterator fetching its pages with the given Pager.
+func NewIterator[T any](pager Pager[T]) *Iterator[T] {
+	return &Iterator[T]{pager: pager}
+}
+
+// Max limits the total number of items returned by the iterator.
+// Values lower than 1 remove the limit.
+func (it *Iterator[T]) Max(max int) *Iterator[T] {
+	it.max = max
+	return it
+}
+
+// Cursor returns the cursor of the next page.
+func (it *Iterator[T]) Cursor() string {
+	return it.cursor
+}
+
+// Next returns the items of the next page. Returns Done if there are no more pages
+// or the maximum number of items has been reached.
+func (it *Iterator[T]) Next(ctx context.Context) ([]T, error) {
+	if it.done || (it.max > 0 && it.count >= it.max) {
+		return nil, Done
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items, next, err := it.pager(ctx, it.cursor)
+	if err != nil {
+		return nil, err
+	}
+	// Stop if there is no next page or the API keeps returning the same cursor
+	if next == "" || next == it.cursor {
+		it.done = true
+	}
+	it.cursor = next
+
+	if it.max > 0 && it.count+len(items) > it.max {
+		items = items[:it.max-it.count]
+	}
+	it.count += len(items)
+
+	if len(items) == 0 && it.done {
+		return nil, Done
+	}
+	return items, nil
+}
+
+// All returns the items of all remaining pages.
+// On error, the items fetched so far are returned along with the error.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for {
+		items, err := it.Next(ctx)
+		if err == Done {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+iterator_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package social
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// pages returns a Pager serving the given pages. The cursor of a page is its index, the next cursor of
+// the last page is given by last. The requested cursors are appended to the given slice.
+func pages(items [][]int, last string, cursors *[]string) Pager[int] {
+	index := map[string]int{"": 0, "1": 1, "2": 2, "3": 3}
+	return func(ctx context.Context, cursor string) ([]int, string, error) {
+		*cursors = append(*cursors, cursor)
+		i := index[cursor]
+		if i == len(items)-1 {
+			return items[i], last, nil
+		}
+		return items[i], []string{"1", "2", "3"}[i], nil
+	}
+}
+
+func TestIteratorAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   [][]int
+		last    string
+		max     int
+		want    []int
+		cursors []string
+	}{
+		{"single page", [][]int{{1, 2}}, "", 0, []int{1, 2}, []string{""}},
+		{"multiple pages", [][]int{{1, 2}, {3}, {4, 5}}, "", 0, []int{1, 2, 3, 4, 5}, []string{"", "1", "2"}},
+		{"empty last page", [][]int{{1, 2}, {}}, "", 0, []int{1, 2}, []string{"", "1"}},
+		{"max within page", [][]int{{1, 2}, {3, 4}}, "", 3, []int{1, 2, 3}, []string{"", "1"}},
+		{"max on page boundary", [][]int{{1, 2}, {3, 4}}, "", 2, []int{1, 2}, []string{""}},
+		{"max below 1", [][]int{{1, 2}, {3}}, "", -1, []int{1, 2, 3}, []string{"", "1"}},
+		// The API keeps returning the cursor of the last page
+		{"repeated cursor", [][]int{{1, 2}, {3}}, "1", 0, []int{1, 2, 3}, []string{"", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursors []string
+			got, err := NewIterator(pages(tt.items, tt.last, &cursors)).Max(tt.max).All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(cursors, tt.cursors) {
+				t.Errorf("requested cursors %q, want %q", cursors, tt.cursors)
+			}
+		})
+	}
+}
+
+func TestIteratorNext(t *testing.T) {
+	var cursors []string
+	it := NewIterator(pages([][]int{{1, 2}, {3}}, "", &cursors))
+	ctx := context.Background()
+
+	if items, err := it.Next(ctx); err != nil || !reflect.DeepEqual(items, []int{1, 2}) || it.Cursor() != "1" {
+		t.Errorf("first page = %v, %v with cursor %q, want [1 2] with cursor 1", items, err, it.Cursor())
+	}
+	if items, err := it.Next(ctx); err != nil || !reflect.DeepEqual(items, []int{3}) || it.Cursor() != "" {
+		t.Errorf("last page = %v, %v with cursor %q, want [3] without cursor", items, err, it.Cursor())
+	}
+	// Done is returned without requesting another page, also on further calls
+	for i := 0; i < 2; i++ {
+		if items, err := it.Next(ctx); err != Done || items != nil {
+			t.Errorf("Next() after the last page = %v, %v, want Done", items, err)
+		}
+	}
+	if len(cursors) != 2 {
+		t.Errorf("requested %d pages, want 2", len(cursors))
+	}
+}
+
+func TestIteratorErrors(t *testing.T) {
+	pageErr := errors.New("page failed")
+	it := NewIterator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		if cursor == "" {
+			return []int{1, 2}, "1", nil
+		}
+		return nil, "", pageErr
+	})
+
+	// The items fetched before the error are returned along with it
+	got, err := it.All(context.Background())
+	if !errors.Is(err, pageErr) || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("All() = %v, %v, want [1 2] and the page error", got, err)
+	}
+	// The failed page is requested again by the next call
+	if it.Cursor() != "1" {
+		t.Errorf("cursor = %q after the error, want 1", it.Cursor())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewIterator(it.pager).Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next() with a canceled context = %v, want %v", err, context.Canceled)
+	}
+}
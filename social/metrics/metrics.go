@@ -0,0 +1,144 @@
+/*
+metrics.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+// Package metrics records the follower, following and content counts of the providers over time.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"github.com/emrearmagan/go-social/models"
+	"github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"time"
+)
+
+// Point are the counts of an account at a point in time.
+type Point struct {
+	// Provider is the name of the provider, e.g. "twitter"
+	Provider string `json:"provider"`
+	// Account is the name of the account, which is empty for a single account
+	Account      string    `json:"account,omitempty"`
+	Time         time.Time `json:"time"`
+	Followers    int       `json:"followers"`
+	Following    *int      `json:"following,omitempty"` // nil if the provider does not provide it
+	ContentCount int64     `json:"content_count"`
+}
+
+// PointOf returns the counts of the given user as Point. The time of the Point is the FetchedAt
+// of the user or the current time if it is not set.
+func PointOf(account string, user *models.SocialUser) Point {
+	t := user.FetchedAt
+	if t.IsZero() {
+		t = time.Now()
+	}
+	p := Point{
+		Provider:     user.Provider,
+		Account:      account,
+		Time:         t,
+		Followers:    user.Followers,
+		ContentCount: user.ContentCount,
+	}
+	if user.Following != nil {
+		following := *user.Following
+		p.Following = &following
+	}
+	return p
+}
+
+// Metric selects a count of a Point.
+type Metric string
+
+const (
+	Followers Metric = "followers"
+	Following Metric = "following"
+	Content   Metric = "content"
+)
+
+// value returns the count of the Point. Returns false if the Point has no such count.
+func (m Metric) value(p Point) (float64, bool) {
+	switch m {
+	case Followers:
+		return float64(p.Followers), true
+	case Following:
+		if p.Following == nil {
+			return 0, false
+		}
+		return float64(*p.Following), true
+	case Content:
+		return float64(p.ContentCount), true
+	}
+	return 0, false
+}
+
+// Source is an account recorded by a Recorder.
+type Source struct {
+	Provider social.Provider
+	// Account is the name of the account, which is empty for a single account
+	Account string
+}
+
+// Recorder records the counts of the providers into a Store.
+type Recorder struct {
+	store   Store
+	onError func(source Source, err error)
+}
+
+// NewRecorder returns a new Recorder appending the points to the given Store.
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{store: store}
+}
+
+// OnError sets a callback which is called with the errors of Run, e.g. for logging them.
+func (r *Recorder) OnError(fn func(source Source, err error)) {
+	r.onError = fn
+}
+
+// Record fetches the user of the provider and appends its counts to the Store.
+func (r *Recorder) Record(ctx context.Context, p social.Provider, account string) (Point, error) {
+	user, err := p.GoSocialUserContext(ctx)
+	if err != nil {
+		return Point{}, err
+	}
+
+	point := PointOf(account, user)
+	if point.Provider == "" {
+		point.Provider = p.Name()
+	}
+	return point, r.store.Append(point)
+}
+
+// Run records the sources immediately and then every interval until the context is done.
+// Failing sources do not stop the Recorder, their errors are passed to the OnError callback.
+// Returns the error of the context or an ErrBadRequest SocialError if the interval is not positive.
+func (r *Recorder) Run(ctx context.Context, interval time.Duration, sources ...Source) error {
+	if interval <= 0 {
+		return errors.New(errors.ErrBadRequest, fmt.Sprintf("metrics: non-positive interval %v", interval))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, source := range sources {
+			if _, err := r.Record(ctx, source.Provider, source.Account); err != nil && r.onError != nil {
+				r.onError(source, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Series returns the points of the account recorded between from and to. Zero times are not bounded.
+func (r *Recorder) Series(provider, account string, from, to time.Time) (Series, error) {
+	points, err := r.store.Range(provider, account, from, to)
+	return Series(points), err
+}
@@ -0,0 +1,109 @@
+/*
+metrics_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"testing"
+	"time"
+)
+
+// fakeProvider returns its user or fails with err.
+type fakeProvider struct {
+	user *models.SocialUser
+	err  error
+}
+
+func (p *fakeProvider) Name() string                      { return "fake" }
+func (p *fakeProvider) Capabilities() social.Capabilities { return 0 }
+func (p *fakeProvider) GoSocialUser() (*models.SocialUser, error) {
+	return p.GoSocialUserContext(context.Background())
+}
+func (p *fakeProvider) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	return p.user, p.err
+}
+func (p *fakeProvider) FollowersIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+func (p *fakeProvider) FollowingIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+func (p *fakeProvider) RefreshContext(ctx context.Context) error { return social.ErrUnsupported }
+func (p *fakeProvider) RevokeContext(ctx context.Context) error  { return social.ErrUnsupported }
+
+func TestPointOf(t *testing.T) {
+	following := 7
+	fetched := date(3).Add(time.Hour)
+	user := &models.SocialUser{Provider: "github", Followers: 12, Following: &following, ContentCount: 30, FetchedAt: fetched}
+
+	p := PointOf("work", user)
+	if p.Provider != "github" || p.Account != "work" || !p.Time.Equal(fetched) || p.Followers != 12 || p.ContentCount != 30 {
+		t.Errorf("PointOf = %+v", p)
+	}
+	// The following count is copied, so changing the user does not change the Point
+	following = 8
+	if p.Following == nil || *p.Following != 7 {
+		t.Errorf("Following = %v, want 7", p.Following)
+	}
+
+	before := time.Now()
+	p = PointOf("", &models.SocialUser{Provider: "spotify"})
+	if p.Following != nil || p.Time.Before(before) {
+		t.Errorf("PointOf of a user without FetchedAt and Following = %+v, want the current time and no Following", p)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	store := NewMemoryStore()
+	r := NewRecorder(store)
+	ctx := context.Background()
+
+	// The name of the provider is used if the user has none
+	point, err := r.Record(ctx, &fakeProvider{user: &models.SocialUser{Followers: 3, FetchedAt: date(1)}}, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if point.Provider != "fake" || point.Account != "work" || point.Followers != 3 {
+		t.Errorf("point = %+v", point)
+	}
+	if _, err := r.Record(ctx, &fakeProvider{user: &models.SocialUser{Provider: "other", FetchedAt: date(1)}}, "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failed fetches are not stored
+	rateLimit := socialErrors.New(socialErrors.ErrRateLimit, "rate limit exceeded")
+	if _, err := r.Record(ctx, &fakeProvider{err: rateLimit}, "work"); !errors.Is(err, socialErrors.ErrRateLimit) {
+		t.Errorf("error = %v, want %v", err, socialErrors.ErrRateLimit)
+	}
+
+	series, err := r.Series("fake", "work", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0] != point {
+		t.Errorf("series = %+v, want the recorded point", series)
+	}
+}
+
+func TestRunInterval(t *testing.T) {
+	r := NewRecorder(NewMemoryStore())
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := r.Run(context.Background(), interval); !errors.Is(err, socialErrors.ErrBadRequest) {
+			t.Errorf("Run(%v) = %v, want ErrBadRequest", interval, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Run(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run with a canceled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+series.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package metrics
+
+import (
+	"time"
+)
+
+// Series are the points of a single account, the oldest first.
+type Series []Point
+
+// Growth is the change of a metric over a window.
+type Growth struct {
+	Metric Metric    `json:"metric"`
+	From   time.Time `json:"from"` // Time of the first point of the window
+	To     time.Time `json:"to"`   // Time of the last point of the window
+	Start  float64   `json:"start"`
+	End    float64   `json:"end"`
+	Change float64   `json:"change"`
+	// Percent is the change relative to Start. Zero if Start is zero
+	Percent float64 `json:"percent"`
+}
+
+// Growth returns the change of the metric between the first and the last point recorded between from and to.
+// Zero times are not bounded. Returns false if the window has no point with the metric.
+func (s Series) Growth(metric Metric, from, to time.Time) (Growth, bool) {
+	var g Growth
+	found := false
+	for _, p := range s {
+		if (!from.IsZero() && p.Time.Before(from)) || (!to.IsZero() && p.Time.After(to)) {
+			continue
+		}
+		v, ok := metric.value(p)
+		if !ok {
+			continue
+		}
+		if !found {
+			g.From, g.Start = p.Time, v
+			found = true
+		}
+		g.To, g.End = p.Time, v
+	}
+	if !found {
+		return Growth{}, false
+	}
+
+	g.Metric = metric
+	g.Change = g.End - g.Start
+	if g.Start != 0 {
+		g.Percent = g.Change / g.Start * 100
+	}
+	return g, true
+}
+
+// Day is the value of a metric at the end of a day and its change on that day.
+type Day struct {
+	// Date is the start of the day
+	Date time.Time `json:"date"`
+	// Value is the value of the last point of the day
+	Value float64 `json:"value"`
+	// Delta is the change compared to the previous day with points. The Delta of the first day
+	// is the change between the first and the last point of the day.
+	Delta float64 `json:"delta"`
+	// Average is the average Delta of the trailing window of days. Only set by MovingAverage
+	Average float64 `json:"average,omitempty"`
+}
+
+// Days are the days of a Series, the oldest first.
+type Days []Day
+
+// DailyDeltas returns the value and the change of the metric per day in the given location.
+// Days without points are skipped, so their change is included in the next day with points.
+func (s Series) DailyDeltas(metric Metric, loc *time.Location) Days {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var days Days
+	var previous float64
+	for _, p := range s {
+		v, ok := metric.value(p)
+		if !ok {
+			continue
+		}
+		t := p.Time.In(loc)
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+		if len(days) == 0 {
+			previous = v
+		}
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			if len(days) > 0 {
+				previous = days[len(days)-1].Value
+			}
+			days = append(days, Day{Date: date})
+		}
+		day := &days[len(days)-1]
+		day.Value = v
+		day.Delta = v - previous
+	}
+	return days
+}
+
+// FillGaps returns the days with the calendar days missing between them added. Since the change of a missing
+// day is included in the next day with points, the added days have the Value of the previous day and a zero Delta.
+func (d Days) FillGaps() Days {
+	if len(d) == 0 {
+		return nil
+	}
+
+	filled := make(Days, 0, len(d))
+	for _, day := range d {
+		if len(filled) > 0 {
+			previous := filled[len(filled)-1]
+			for date := previous.Date.AddDate(0, 0, 1); date.Before(day.Date); date = date.AddDate(0, 0, 1) {
+				filled = append(filled, Day{Date: date, Value: previous.Value})
+			}
+		}
+		filled = append(filled, day)
+	}
+	return filled
+}
+
+// MovingAverage returns the average Delta of the trailing window of calendar days for every day.
+// Missing days are filled with a zero Delta, see FillGaps. The returned days keep their Value and Delta and
+// have the Average set, the first window-1 days are skipped.
+func (d Days) MovingAverage(window int) Days {
+	d = d.FillGaps()
+	if window < 1 || len(d) < window {
+		return nil
+	}
+
+	averages := make(Days, 0, len(d)-window+1)
+	var sum float64
+	for i, day := range d {
+		sum += day.Delta
+		if i >= window {
+			sum -= d[i-window].Delta
+		}
+		if i >= window-1 {
+			day.Average = sum / float64(window)
+			averages = append(averages, day)
+		}
+	}
+	return averages
+}
+
+// Best returns the day with the highest Delta. Returns false if there are no days.
+func (d Days) Best() (Day, bool) {
+	return d.pick(func(a, b Day) bool { return a.Delta > b.Delta })
+}
+
+// Worst returns the day with the lowest Delta. Returns false if there are no days.
+func (d Days) Worst() (Day, bool) {
+	return d.pick(func(a, b Day) bool { return a.Delta < b.Delta })
+}
+
+// pick returns the first day for which better returns true compared to all other days.
+func (d Days) pick(better func(a, b Day) bool) (Day, bool) {
+	if len(d) == 0 {
+		return Day{}, false
+	}
+	best := d[0]
+	for _, day := range d[1:] {
+		if better(day, best) {
+			best = day
+		}
+	}
+	return best, true
+}
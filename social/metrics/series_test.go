@@ -0,0 +1,135 @@
+/*
+series_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(day int) time.Time {
+	return time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGrowth(t *testing.T) {
+	following := 5
+	series := Series{
+		{Time: date(1), Followers: 0, ContentCount: 10},
+		{Time: date(2), Followers: 10, Following: &following, ContentCount: 12},
+		{Time: date(3), Followers: 15, ContentCount: 12},
+		{Time: date(4), Followers: 12, Following: &following, ContentCount: 18},
+	}
+	tests := []struct {
+		name     string
+		metric   Metric
+		from, to time.Time
+		want     Growth
+		ok       bool
+	}{
+		{"unbounded", Content, time.Time{}, time.Time{}, Growth{Metric: Content, From: date(1), To: date(4), Start: 10, End: 18, Change: 8, Percent: 80}, true},
+		{"bounded", Followers, date(2), date(3), Growth{Metric: Followers, From: date(2), To: date(3), Start: 10, End: 15, Change: 5, Percent: 50}, true},
+		{"bounded from", Followers, date(3), time.Time{}, Growth{Metric: Followers, From: date(3), To: date(4), Start: 15, End: 12, Change: -3, Percent: -20}, true},
+		// The percentage of a change from zero is undefined
+		{"start zero", Followers, time.Time{}, date(2), Growth{Metric: Followers, From: date(1), To: date(2), Start: 0, End: 10, Change: 10}, true},
+		// Points without the metric are skipped
+		{"missing metric", Following, time.Time{}, time.Time{}, Growth{Metric: Following, From: date(2), To: date(4), Start: 5, End: 5}, true},
+		{"single point", Followers, date(3), date(3), Growth{Metric: Followers, From: date(3), To: date(3), Start: 15, End: 15}, true},
+		{"empty window", Followers, date(5), time.Time{}, Growth{}, false},
+		{"no point with metric", Following, date(3), date(3), Growth{}, false},
+		{"unknown metric", Metric("likes"), time.Time{}, time.Time{}, Growth{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := series.Growth(tt.metric, tt.from, tt.to)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Growth = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDailyDeltas(t *testing.T) {
+	series := Series{
+		{Time: date(1).Add(time.Hour), Followers: 10},
+		{Time: date(1).Add(20 * time.Hour), Followers: 12},
+		{Time: date(2).Add(time.Hour), Followers: 15},
+		// No points on day 3 and 4
+		{Time: date(5).Add(time.Hour), Followers: 21},
+	}
+	want := Days{
+		{Date: date(1), Value: 12, Delta: 2},
+		{Date: date(2), Value: 15, Delta: 3},
+		{Date: date(5), Value: 21, Delta: 6},
+	}
+	if got := series.DailyDeltas(Followers, time.UTC); !reflect.DeepEqual(got, want) {
+		t.Errorf("DailyDeltas = %+v, want %+v", got, want)
+	}
+}
+
+func TestFillGaps(t *testing.T) {
+	days := Days{
+		{Date: date(1), Value: 12, Delta: 2},
+		{Date: date(4), Value: 21, Delta: 9},
+	}
+	want := Days{
+		{Date: date(1), Value: 12, Delta: 2},
+		{Date: date(2), Value: 12},
+		{Date: date(3), Value: 12},
+		{Date: date(4), Value: 21, Delta: 9},
+	}
+	if got := days.FillGaps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FillGaps = %+v, want %+v", got, want)
+	}
+}
+
+// TestMovingAverageGaps checks that the window spans calendar days, not days with points.
+func TestMovingAverageGaps(t *testing.T) {
+	days := Days{
+		{Date: date(1), Value: 10, Delta: 3},
+		{Date: date(2), Value: 13, Delta: 3},
+		// No points on day 3 and 4, their change is included in day 5
+		{Date: date(5), Value: 19, Delta: 6},
+	}
+	got := days.MovingAverage(3)
+	want := Days{
+		{Date: date(3), Value: 13, Delta: 0, Average: 2},
+		{Date: date(4), Value: 13, Delta: 0, Average: 1},
+		{Date: date(5), Value: 19, Delta: 6, Average: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MovingAverage = %+v, want %+v", got, want)
+	}
+
+	if got := days.MovingAverage(6); got != nil {
+		t.Errorf("MovingAverage with a window longer than the days = %+v, want nil", got)
+	}
+}
+
+// TestBestWorst checks that the first of several days with the same Delta is returned.
+func TestBestWorst(t *testing.T) {
+	days := Days{
+		{Date: date(1), Delta: 2},
+		{Date: date(2), Delta: 5},
+		{Date: date(3), Delta: -1},
+		{Date: date(4), Delta: 5},
+		{Date: date(5), Delta: -1},
+	}
+	if best, ok := days.Best(); !ok || !best.Date.Equal(date(2)) {
+		t.Errorf("Best = %+v, %v, want day 2", best, ok)
+	}
+	if worst, ok := days.Worst(); !ok || !worst.Date.Equal(date(3)) {
+		t.Errorf("Worst = %+v, %v, want day 3", worst, ok)
+	}
+
+	if _, ok := (Days{}).Best(); ok {
+		t.Error("Best of no days = true, want false")
+	}
+	if _, ok := Days(nil).Worst(); ok {
+		t.Error("Worst of no days = true, want false")
+	}
+}
@@ -0,0 +1,184 @@
+/*
+store.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const storeFileMode = 0600
+
+// Store persists the points of the accounts as time series.
+type Store interface {
+	// Append adds the point to the series of its provider and account.
+	Append(point Point) error
+	// Range returns the points of the provider and account recorded between from and to, the oldest first.
+	// Zero times are not bounded.
+	Range(provider, account string, from, to time.Time) ([]Point, error)
+}
+
+// inRange returns true if the point belongs to the provider and account and was recorded between from and to.
+func inRange(p Point, provider, account string, from, to time.Time) bool {
+	if p.Provider != provider || p.Account != account {
+		return false
+	}
+	if !from.IsZero() && p.Time.Before(from) {
+		return false
+	}
+	return to.IsZero() || !p.Time.After(to)
+}
+
+// sortPoints sorts the points by time, keeping the order of points with the same time.
+func sortPoints(points []Point) {
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+}
+
+// MemoryStore is a Store keeping the points in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	points []Point
+}
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append adds the point.
+func (s *MemoryStore) Append(point Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.points = append(s.points, point)
+	return nil
+}
+
+// Range returns the points of the provider and account recorded between from and to, the oldest first.
+func (s *MemoryStore) Range(provider, account string, from, to time.Time) ([]Point, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var points []Point
+	for _, p := range s.points {
+		if inRange(p, provider, account, from, to) {
+			points = append(points, p)
+		}
+	}
+	sortPoints(points)
+	return points, nil
+}
+
+// FileStore is a Store appending the points to a file as newline delimited JSON, one point per line.
+// Complete lines are never rewritten, only an incomplete last line of an interrupted Append is truncated
+// by the next Append. It is safe for concurrent use, but not for multiple processes writing the same file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a new FileStore for the file at the given path. The file is created on the first Append.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Append appends the point as a line to the file.
+func (s *FileStore) Append(point Point) error {
+	line, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, storeFileMode)
+	if err != nil {
+		return err
+	}
+	if err := truncateIncomplete(f); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// truncateIncomplete removes an incomplete last line of the file, e.g. of an interrupted Append.
+func truncateIncomplete(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	buf := make([]byte, 4096)
+	for offset := end; offset > 0; {
+		n := int64(len(buf))
+		if n > offset {
+			n = offset
+		}
+		offset -= n
+		if _, err := f.ReadAt(buf[:n], offset); err != nil {
+			return err
+		}
+		if offset+n == end && buf[n-1] == '\n' {
+			// The file is complete
+			return nil
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			return f.Truncate(offset + int64(i) + 1)
+		}
+	}
+	return f.Truncate(0)
+}
+
+// Range reads the file and returns the points of the provider and account recorded between from and to, the oldest first.
+// A missing file has no points. An incomplete last line, e.g. of an interrupted Append, is ignored.
+func (s *FileStore) Range(provider, account string, from, to time.Time) ([]Point, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	lines := bytes.Split(content, []byte{'\n'})
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		var p Point
+		if err := json.Unmarshal(line, &p); err != nil {
+			// Only the last line can be incomplete, it is not terminated by a newline
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, err
+		}
+		if inRange(p, provider, account, from, to) {
+			points = append(points, p)
+		}
+	}
+	sortPoints(points)
+	return points, nil
+}
@@ -0,0 +1,60 @@
+/*
+store_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFileStoreIncompleteLine checks that an incomplete last line of an interrupted Append is ignored
+// by Range and truncated by the next Append, while complete lines are kept.
+func TestFileStoreIncompleteLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.ndjson")
+	store := NewFileStore(path)
+	if err := store.Append(Point{Provider: "github", Time: date(1), Followers: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`{"provider":"github","time":"2026-10-02T00:00:00Z","follo`))
+	f.Close()
+
+	points, err := store.Range("github", "", date(1), date(31))
+	if err != nil || len(points) != 1 {
+		t.Fatalf("Range = %+v, %v, want the complete point", points, err)
+	}
+
+	if err := store.Append(Point{Provider: "github", Time: date(3), Followers: 3}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("incomplete line %q was kept", line)
+		}
+	}
+	if len(lines) != 2 {
+		t.Errorf("file = %q, want two lines", content)
+	}
+
+	points, err = store.Range("github", "", date(1), date(31))
+	if err != nil || len(points) != 2 || points[1].Followers != 3 {
+		t.Errorf("Range = %+v, %v, want both points", points, err)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/social/client"
+	"sort"
+	"sync"
+)
+
+// ErrUnsupported is returned by the methods of a Provider which are not supported by the API,
+// e.g. refreshing the token of an OAuth1 provider.
+var ErrUnsupported = errors.New("social: not supported by the provider")
+
+// Capabilities describe which methods of a Provider are supported.
+type Capabilities uint
+
+const (
+	// CapFollowers is set if the Provider can list the followers of the user.
+	CapFollowers Capabilities = 1 << iota
+	// CapFollowing is set if the Provider can list the users, channels or artists the user follows.
+	CapFollowing
+	// CapRefresh is set if the Provider can refresh the token.
+	CapRefresh
+	// CapRevoke is set if the Provider can revoke the token.
+	CapRevoke
+)
+
+// Has returns true if all the given capabilities are set.
+func (c Capabilities) Has(capabilities Capabilities) bool {
+	return c&capabilities == capabilities
+}
+
+// Provider is implemented by the Client of every provider package, so the providers can be used
+// without knowing their concrete type. Methods which are not supported return ErrUnsupported,
+// see Capabilities.
+type Provider interface {
+	// Name returns the provider name, e.g. "twitter".
+	Name() string
+	// Capabilities returns the supported methods of the provider.
+	Capabilities() Capabilities
+	// GoSocialUser returns the authenticated user.
+	GoSocialUser() (*models.SocialUser, error)
+	// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+	GoSocialUserContext(ctx context.Context) (*models.SocialUser, error)
+	// FollowersIterator returns an Iterator over the ids of the followers of the authenticated user.
+	FollowersIterator() *Iterator[string]
+	// FollowingIterator returns an Iterator over the ids the authenticated user follows.
+	FollowingIterator() *Iterator[string]
+	// RefreshContext refreshes the token.
+	RefreshContext(ctx context.Context) error
+	// RevokeContext revokes the token.
+	RevokeContext(ctx context.Context) error
+}
+
+// Account holds everything a Provider is built from, e.g. an entry of a config file.
+type Account struct {
+	Credentials oauth.Credentials
+	// Token is a *oauth1.Token for OAuth1 providers and a *oauth2.Token for OAuth2 providers.
+	Token interface{}
+	// UserAgent is sent with every request. It is required by some providers, like Reddit.
+	UserAgent string
+	// Options configure the http client of the provider.
+	Options []client.Option
+}
+
+// Factory builds a Provider for the given Account.
+type Factory func(ctx context.Context, account Account) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available by the given name. The provider packages register themselves
+// when they are imported, so third party providers can be registered the same way.
+// If Register is called twice with the same name or if the factory is nil, it panics.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("social: Register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("social: Register called twice for provider " + name)
+	}
+	registry[name] = factory
+}
+
+// Get returns the Factory registered with the given name.
+func Get(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// New builds the Provider registered with the given name for the account.
+func New(ctx context.Context, name string, account Account) (Provider, error) {
+	factory, ok := Get(name)
+	if !ok {
+		return nil, socialErrors.New(socialErrors.ErrNotFound, fmt.Sprintf("social: unknown provider %q (forgotten import?)", name))
+	}
+	return factory(ctx, account)
+}
+
+// Providers returns the sorted names of the registered providers.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TokenTypeError returns the error of a Factory for an account with a token of the wrong type.
+func TokenTypeError(provider string, token interface{}) error {
+	return socialErrors.New(socialErrors.ErrBadAuthenticationData, fmt.Sprintf("%s: unsupported token type %T", provider, token))
+}
+
+// MapIterator returns an Iterator over the items of the given Iterator converted with fn.
+func MapIterator[T, U any](it *Iterator[T], fn func(T) U) *Iterator[U] {
+	return NewIterator(func(ctx context.Context, _ string) ([]U, string, error) {
+		items, err := it.Next(ctx)
+		if err == Done {
+			return nil, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		mapped := make([]U, len(items))
+		for i, item := range items {
+			mapped[i] = fn(item)
+		}
+		// The cursor of the wrapped Iterator is empty once it reached the last page
+		return mapped, it.Cursor(), nil
+	})
+}
+
+// UnsupportedIterator returns an Iterator failing with ErrUnsupported.
+func UnsupportedIterator[T any]() *Iterator[T] {
+	return NewIterator(func(ctx context.Context, cursor string) ([]T, string, error) {
+		return nil, "", ErrUnsupported
+	})
+}
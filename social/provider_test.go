@@ -0,0 +1,167 @@
+/*
+provider_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package social
+
+import (
+	"context"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The provider packages cannot be imported by the tests of this package, so the registry only
+// contains the providers registered by the tests. Every test registers its own names.
+
+func nopFactory(ctx context.Context, account Account) (Provider, error) {
+	return nil, nil
+}
+
+// panics returns the value fn panicked with or nil.
+func panics(fn func()) (v interface{}) {
+	defer func() {
+		v = recover()
+	}()
+	fn()
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-register", nopFactory)
+	if factory, ok := Get("test-register"); !ok || factory == nil {
+		t.Errorf("Get(test-register) = %v, %v, want the registered factory", factory, ok)
+	}
+	if factory, ok := Get("test-unknown"); ok || factory != nil {
+		t.Errorf("Get(test-unknown) = %v, %v, want nil, false", factory, ok)
+	}
+
+	if v := panics(func() { Register("test-register", nopFactory) }); v == nil || !strings.Contains(v.(string), "test-register") {
+		t.Errorf("registering twice panicked with %v, want the provider name", v)
+	}
+	if v := panics(func() { Register("test-nil", nil) }); v == nil {
+		t.Error("registering a nil factory did not panic")
+	}
+	if _, ok := Get("test-nil"); ok {
+		t.Error("the nil factory was registered")
+	}
+}
+
+func TestNew(t *testing.T) {
+	type key struct{}
+	var got Account
+	var value interface{}
+	factoryErr := errors.New("factory failed")
+	Register("test-new", func(ctx context.Context, account Account) (Provider, error) {
+		got, value = account, ctx.Value(key{})
+		return nil, factoryErr
+	})
+
+	account := Account{Credentials: oauth.Credentials{ConsumerKey: "key"}, Token: "token", UserAgent: "go-social"}
+	ctx := context.WithValue(context.Background(), key{}, "new")
+	if _, err := New(ctx, "test-new", account); err != factoryErr {
+		t.Errorf("error = %v, want the error of the factory", err)
+	}
+	if !reflect.DeepEqual(got, account) || value != "new" {
+		t.Errorf("factory called with %+v and context value %v, want %+v and new", got, value, account)
+	}
+
+	p, err := New(context.Background(), "test-unknown", account)
+	if p != nil || !errors.Is(err, socialErrors.ErrNotFound) {
+		t.Errorf("New(test-unknown) = %v, %v, want %v", p, err, socialErrors.ErrNotFound)
+	}
+	if err != nil && !strings.Contains(err.Error(), `"test-unknown"`) {
+		t.Errorf("error = %q, want the provider name", err)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	Register("test-providers-b", nopFactory)
+	Register("test-providers-a", nopFactory)
+
+	names := Providers()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Providers() = %v, want sorted names", names)
+	}
+	var registered []string
+	for _, name := range names {
+		if strings.HasPrefix(name, "test-providers-") {
+			registered = append(registered, name)
+		}
+	}
+	if want := []string{"test-providers-a", "test-providers-b"}; !reflect.DeepEqual(registered, want) {
+		t.Errorf("Providers() contains %v, want %v", registered, want)
+	}
+}
+
+func TestTokenTypeError(t *testing.T) {
+	err := TokenTypeError("twitter", "token")
+	if !errors.Is(err, socialErrors.ErrBadAuthenticationData) {
+		t.Errorf("error = %v, want %v", err, socialErrors.ErrBadAuthenticationData)
+	}
+	if !strings.Contains(err.Error(), "twitter") || !strings.Contains(err.Error(), "string") {
+		t.Errorf("error = %q, want the provider and the token type", err)
+	}
+}
+
+func TestCapabilitiesHas(t *testing.T) {
+	c := CapFollowers | CapRefresh
+	if !c.Has(CapFollowers) || !c.Has(CapFollowers|CapRefresh) || c.Has(CapFollowing) || c.Has(CapFollowers|CapRevoke) {
+		t.Errorf("Has of %b returned wrong results", c)
+	}
+	if !Capabilities(0).Has(0) {
+		t.Error("Has(0) = false, want true")
+	}
+}
+
+func TestMapIterator(t *testing.T) {
+	var cursors []string
+	it := MapIterator(NewIterator(pages([][]int{{1, 2}, {3}, {4}}, "", &cursors)), func(i int) string {
+		return strings.Repeat("x", i)
+	})
+
+	got, err := it.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "xx", "xxx", "xxxx"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if want := []string{"", "1", "2"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("requested cursors %q, want %q", cursors, want)
+	}
+
+	// Errors of the wrapped Iterator are returned with the items mapped so far
+	pageErr := errors.New("page failed")
+	failing := NewIterator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		if cursor == "" {
+			return []int{1, 2}, "1", nil
+		}
+		return nil, "", pageErr
+	})
+	mapped := MapIterator(failing, func(i int) int { return i * 10 })
+	if got, err := mapped.All(context.Background()); !errors.Is(err, pageErr) || !reflect.DeepEqual(got, []int{10, 20}) {
+		t.Errorf("All() = %v, %v, want [10 20] and the page error", got, err)
+	}
+	// Max applies to the mapped items
+	limited := MapIterator(NewIterator(pages([][]int{{1, 2}, {3}}, "", new([]string))), func(i int) int { return i })
+	if got, err := limited.Max(1).All(context.Background()); err != nil || !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("All() with Max(1) = %v, %v, want [1]", got, err)
+	}
+}
+
+func TestUnsupportedIterator(t *testing.T) {
+	it := UnsupportedIterator[string]()
+	if items, err := it.Next(context.Background()); !errors.Is(err, ErrUnsupported) || items != nil {
+		t.Errorf("Next() = %v, %v, want %v", items, err, ErrUnsupported)
+	}
+	if items, err := it.All(context.Background()); !errors.Is(err, ErrUnsupported) || len(items) != 0 {
+		t.Errorf("All() = %v, %v, want %v", items, err, ErrUnsupported)
+	}
+}
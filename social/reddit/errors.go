@@ -9,12 +9,18 @@ package reddit
 import (
 	"fmt"
 	"github.com/emrearmagan/go-social/models/errors"
+	"strconv"
+	"time"
 )
 
 // APIError represents a Reddit API error with its corresponding http StatusCode response
 type APIError struct {
 	StatusCode int
 	Errors     ErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 type ErrorDetail struct {
@@ -30,12 +36,15 @@ func (e *APIError) Error() string {
 	if (e.Errors != ErrorDetail{}) {
 		return fmt.Sprintf("Reddit: %d - %v : %v", e.StatusCode, e.Errors.Error, e.Errors.Message)
 	}
+	if e.Body != "" {
+		return fmt.Sprintf("Reddit: %d - %v", e.StatusCode, e.Body)
+	}
 	return fmt.Sprintf("Reddit: %d", e.StatusCode)
 }
 
 // Empty returns true if nil. Otherwise, at least 1 error message/code is
 func (e *APIError) Empty() bool {
-	return e.Errors == ErrorDetail{}
+	return e.Errors == ErrorDetail{} && e.Body == ""
 }
 
 func (e *APIError) Status() int {
@@ -46,18 +55,43 @@ func (e *APIError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *APIError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *APIError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 func (e *APIError) ReturnErrorResponse() error {
 	switch e.Status() {
-	case 304: // The content has not been modified and client should use cached data
-		return errors.New(errors.ErrNotModified, e.Error())
-	case 401, 403: // Invalid or expired token (if token has been revoked) - The access token used in the request is incorrect or has expired.
-		return errors.New(errors.ErrUnauthorized, e.Error())
-	case 429: // Rate limit exceeded	 - The request limit for this resource has been reached for the current rate limit window.
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case 400, 411:
-		return errors.New(errors.ErrBadRequest, e.Error())
-	case 500, 502, 503: //Internal api error
-		return errors.New(errors.ErrApiError, e.Error())
+	case 411:
+		return e.socialError(errors.ErrBadRequest)
+	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// socialError maps the APIError onto a SocialError of the given kind.
+func (e *APIError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       e.code(),
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
+	}
+}
+
+// code returns the error code of the response body, if any.
+func (e *APIError) code() string {
+	if e.Errors.Error == 0 {
+		return ""
 	}
-	return errors.New(errors.ErrUnknownError, e.Error())
+	return strconv.Itoa(e.Errors.Error)
 }
@@ -0,0 +1,34 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package reddit
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "unauthorized", Status: 401, ContentType: "application/json", Body: `{"message":"Unauthorized","error":401}`, Kind: socialErrors.ErrUnauthorized, Code: "401"},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"message":"Forbidden","error":403}`, Kind: socialErrors.ErrForbidden, Code: "403"},
+		{Name: "length required", Status: 411, ContentType: "application/json", Body: `{"message":"Length Required","error":411}`, Kind: socialErrors.ErrBadRequest, Code: "411"},
+		{Name: "too many requests", Status: 429, ContentType: "application/json", Body: `{"message":"Too Many Requests","error":429}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Code: "429", Retryable: true},
+		{Name: "server error", Status: 500, ContentType: "application/json", Body: `{"message":"Internal Server Error","error":500}`, Kind: socialErrors.ErrApiError, Code: "500", Retryable: true},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), "go-social-test", client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background())
+		return err
+	})
+}
@@ -14,9 +14,13 @@ import (
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
 	"strings"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "reddit"
+
 	Base               = "https://oauth.reddit.com"
 	UserAgentHeaderKey = "User-Agent"
 
@@ -24,6 +28,16 @@ const (
 	RefreshPath = "/api/v1/access_token"
 )
 
+// Endpoint is Reddit's OAuth2 endpoint.
+// Reddit only issues a refresh token if the duration is requested as permanent:
+// oauth2.SetAuthURLParam("duration", "permanent")
+// https://github.com/reddit-archive/reddit/wiki/OAuth2
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://www.reddit.com/api/v1/authorize",
+	TokenURL:  "https://www.reddit.com/api/v1/access_token",
+	AuthStyle: oauth2.AuthStyleInHeader,
+}
+
 type Client struct {
 	oauth2 *oauth2.OAuth2
 
@@ -33,38 +47,81 @@ type Client struct {
 // NewClient returns a new Reddit Client.
 // Reddit API requires the UserAgent header for the authenticated application.
 // It is usually in the form of: 'platform:name:1.0 (by /u/username)'. Platform would be for example ios for an registered ios application. 1.0 is the authentication version
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, userAgent string) *Client {
-	cl := client.NewHttpClient().Base(Base).Decoder(redditDecoder{})
-	cl.Set(UserAgentHeaderKey, userAgent)
-	auther := oauth2.NewOAuth(ctx, c, token, cl)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, userAgent string, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
+	cl = cl.Use(client.SetHeader(UserAgentHeaderKey, userAgent))
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
 
-	return &Client{
+	cli := &Client{
 		oauth2: auther,
 		User:   newUserService(auther),
 	}
+
+	// Refresh the token transparently once the API rejects it
+	auther.SetRefreshTokenSource(oauth2.TokenSourceFunc(cli.refreshedToken))
+	return cli
+}
+
+// OnTokenRefresh sets a callback which is called with the new token once it has been refreshed,
+// automatically or with RefreshToken, e.g. for persisting the token.
+func (c *Client) OnTokenRefresh(fn func(token *oauth2.Token)) {
+	c.oauth2.OnTokenRefresh(fn)
+}
+
+// SetTokenStore sets the TokenStore refreshed tokens are saved into. The tokens are keyed by Name and the given account.
+func (c *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	c.oauth2.SetTokenStore(store, Name, account)
+}
+
+// refreshedToken refreshes the token with the context of the rejected request and returns it.
+// Used as TokenSource of the client.
+func (c *Client) refreshedToken(ctx context.Context) (*oauth2.Token, error) {
+	if _, err := c.RefreshTokenContext(ctx); err != nil {
+		return nil, err
+	}
+	return c.oauth2.Token(), nil
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (c *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return c.oauth2.RateLimit(endpoint)
 }
 
 // RefreshToken , a new access token can be generated by supplying the refresh token originally obtained during
 // authorization code exchange.
 // https://github.com/reddit-archive/reddit/wiki/OAuth2#refreshing-the-tok
 func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
+	return c.RefreshTokenContext(c.oauth2.Context())
+}
+
+// RefreshTokenContext is like RefreshToken, but sends the request with the given context.
+func (c *Client) RefreshTokenContext(ctx context.Context) (*oauth2.OAuthRefreshResponse, error) {
 	oauthResp := new(OAuth2RefreshResponse)
 	apiError := new(APIError)
 
 	// Requires basic authentication for refreshing the token even tho the response is bearer....
-	a := c.oauth2.Basic()
+	a := c.oauth2.WithContext(ctx).Basic()
 	err := a.RefreshToken(RefreshBase, RefreshPath, oauthResp, apiError)
+	// Keep the current refresh token if the provider did not issue a new one
+	refreshToken := oauthResp.RefreshToken
+	if refreshToken == "" {
+		refreshToken = c.oauth2.Token().RefreshToken
+	}
+	token := &oauth2.Token{
+		Token:        oauthResp.AccessToken,
+		RefreshToken: refreshToken,
+		TokenType:    oauthResp.TokenType,
+		Expiry:       oauth2.ExpiresIn(oauthResp.ExpiresIn),
+		Scopes:       strings.Fields(oauthResp.Scope),
+	}
 	if err == nil {
-		c.oauth2.UpdateToken(oauth2.NewToken(oauthResp.AccessToken, oauthResp.RefreshToken))
+		err = c.oauth2.UpdateToken(token)
 	}
 	return &oauth2.OAuthRefreshResponse{
-		Token: oauth2.Token{
-			Token:        oauthResp.AccessToken,
-			RefreshToken: oauthResp.RefreshToken,
-		},
+		Token:     *token,
 		TokenType: oauthResp.TokenType,
 		ExpiresIn: oauthResp.ExpiresIn,
-		Scope:     strings.Fields(oauthResp.Scope),
+		Scope:     token.Scopes,
 	}, social.CheckError(err)
 }
 
@@ -77,12 +134,18 @@ type OAuth2RefreshResponse struct {
 }
 
 func (c *Client) GoSocialUser() (*models.SocialUser, error) {
-	u, err := c.User.UserCredentials()
+	return c.GoSocialUserContext(c.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (c *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	u, err := c.User.UserCredentialsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	goSocial := models.SocialUser{
+		Provider:     Name,
 		Username:     u.Name,
 		Name:         u.Subreddit.DisplayName,
 		UserId:       u.ID,
@@ -92,6 +155,10 @@ func (c *Client) GoSocialUser() (*models.SocialUser, error) {
 		AvatarUrl:    u.SnoovatarImg,
 		Followers:    u.Subreddit.Subscribers,
 		Url:          "https://www.reddit.com" + u.Subreddit.URL,
+		Bio:          u.Subreddit.PublicDescription,
+		BannerUrl:    u.Subreddit.BannerImg,
+		CreatedAt:    models.OptionalTime(u.CreatedTime()),
+		FetchedAt:    time.Now(),
 	}
 
 	return &goSocial, nil
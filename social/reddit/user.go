@@ -7,8 +7,11 @@ Copyright © go-social. All rights reserved.
 package reddit
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
+	"math"
+	"time"
 )
 
 const (
@@ -30,10 +33,15 @@ func newUserService(oauth2 *oauth2.OAuth2) *UserService {
 // UserCredentials returns the user credentials for the authenticated user.
 // https://www.reddit.com/dev/api#GET_api_v1_me
 func (u *UserService) UserCredentials() (*User, error) {
+	return u.UserCredentialsContext(u.oauth2.Context())
+}
+
+// UserCredentialsContext is like UserCredentials, but sends the request with the given context.
+func (u *UserService) UserCredentialsContext(ctx context.Context) (*User, error) {
 	user := new(User)
 	apiError := new(APIError)
 
-	err := u.oauth2.Get(UserPath, user, apiError, nil)
+	err := u.oauth2.WithContext(ctx).Get(UserPath, user, apiError, nil)
 	return user, social.CheckError(err)
 }
 
@@ -180,3 +188,12 @@ type User struct {
 	LinkedIdentities        []interface{} `json:"linked_identities"`
 	SeenSubredditChatFtux   bool          `json:"seen_subreddit_chat_ftux"`
 }
+
+// CreatedTime returns the creation date of the account. CreatedUtc is the unix time in seconds.
+func (u *User) CreatedTime() time.Time {
+	if u.CreatedUtc == 0 {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(u.CreatedUtc)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
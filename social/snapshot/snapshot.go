@@ -0,0 +1,195 @@
+/*
+snapshot.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+// Package snapshot stores the complete follower sets of the providers and compares them,
+// e.g. for telling who followed or unfollowed the user.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"sort"
+	"time"
+)
+
+// Kind is the kind of the ids of a Snapshot.
+type Kind string
+
+const (
+	// Followers are the users following the user.
+	Followers Kind = "followers"
+	// Following are the users, channels or artists the user follows.
+	Following Kind = "following"
+)
+
+// Snapshot is the complete set of follower or following ids of an account at a point in time.
+type Snapshot struct {
+	// Provider is the name of the provider, e.g. "twitter"
+	Provider string `json:"provider"`
+	// Account is the name of the account, which is empty for a single account
+	Account string `json:"account,omitempty"`
+	Kind    Kind   `json:"kind"`
+	// IDs are sorted and unique
+	IDs     []string  `json:"ids"`
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// Contains returns true if the snapshot contains the given id.
+func (s *Snapshot) Contains(id string) bool {
+	i := sort.SearchStrings(s.IDs, id)
+	return i < len(s.IDs) && s.IDs[i] == id
+}
+
+// Take fetches all pages of the given Iterator and returns the ids as Snapshot.
+func Take(ctx context.Context, it *social.Iterator[string], provider, account string, kind Kind) (*Snapshot, error) {
+	ids, err := it.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Provider: provider,
+		Account:  account,
+		Kind:     kind,
+		IDs:      unique(ids),
+		TakenAt:  time.Now(),
+	}, nil
+}
+
+// TakeProvider fetches the complete follower or following set of the given Provider.
+// Returns social.ErrUnsupported if the Provider cannot list the given kind, see social.Capabilities.
+func TakeProvider(ctx context.Context, p social.Provider, account string, kind Kind) (*Snapshot, error) {
+	switch kind {
+	case Followers:
+		if !p.Capabilities().Has(social.CapFollowers) {
+			return nil, social.ErrUnsupported
+		}
+		return Take(ctx, p.FollowersIterator(), p.Name(), account, kind)
+	case Following:
+		if !p.Capabilities().Has(social.CapFollowing) {
+			return nil, social.ErrUnsupported
+		}
+		return Take(ctx, p.FollowingIterator(), p.Name(), account, kind)
+	}
+	return nil, socialErrors.New(socialErrors.ErrBadRequest, fmt.Sprintf("snapshot: unknown kind %q", kind))
+}
+
+// unique sorts the ids and removes duplicates, which are returned by some APIs if the set changes while paginating.
+func unique(ids []string) []string {
+	sort.Strings(ids)
+	out := ids[:0]
+	for i, id := range ids {
+		if i == 0 || id != ids[i-1] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Diff is the difference between two snapshots of the same account and kind.
+type Diff struct {
+	Provider string `json:"provider"`
+	Account  string `json:"account,omitempty"`
+	Kind     Kind   `json:"kind"`
+	// From is the time of the older snapshot. Zero if there was no older snapshot
+	From time.Time `json:"from"`
+	// To is the time of the newer snapshot
+	To time.Time `json:"to"`
+
+	// Added are the ids only contained in the newer snapshot, e.g. new followers
+	Added []string `json:"added"`
+	// Removed are the ids only contained in the older snapshot, e.g. lost followers
+	Removed []string `json:"removed"`
+	// Unchanged are the ids contained in both snapshots
+	Unchanged []string `json:"unchanged"`
+}
+
+// Changed returns true if ids have been added or removed.
+func (d Diff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// Compare returns the difference between the older and the newer snapshot.
+// The older snapshot may be nil, e.g. for the first snapshot of an account, so all ids are added.
+// Unsorted ids and duplicates of snapshots built by hand are handled as well.
+func Compare(older, newer *Snapshot) Diff {
+	diff := Diff{
+		Provider:  newer.Provider,
+		Account:   newer.Account,
+		Kind:      newer.Kind,
+		To:        newer.TakenAt,
+		Added:     []string{},
+		Removed:   []string{},
+		Unchanged: []string{},
+	}
+	var old []string
+	if older != nil {
+		diff.From = older.TakenAt
+		old = normalized(older.IDs)
+	}
+	ids := normalized(newer.IDs)
+
+	// Both id sets are sorted, so they are merged in a single pass
+	i, j := 0, 0
+	for i < len(old) && j < len(ids) {
+		switch {
+		case old[i] == ids[j]:
+			diff.Unchanged = append(diff.Unchanged, old[i])
+			i++
+			j++
+		case old[i] < ids[j]:
+			diff.Removed = append(diff.Removed, old[i])
+			i++
+		default:
+			diff.Added = append(diff.Added, ids[j])
+			j++
+		}
+	}
+	diff.Removed = append(diff.Removed, old[i:]...)
+	diff.Added = append(diff.Added, ids[j:]...)
+	return diff
+}
+
+// normalized returns the given ids sorted and unique. The ids of snapshots built by hand are copied
+// if they are not, so the snapshot is not modified.
+func normalized(ids []string) []string {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return unique(append([]string(nil), ids...))
+		}
+	}
+	return ids
+}
+
+// Tracker takes snapshots of the providers and compares them to the previous ones.
+type Tracker struct {
+	store Store
+}
+
+// NewTracker returns a new Tracker saving the snapshots into the given Store.
+func NewTracker(store Store) *Tracker {
+	return &Tracker{store: store}
+}
+
+// Track takes a new snapshot of the provider, compares it to the latest stored snapshot and saves it.
+// The first snapshot of an account is compared to an empty one.
+func (t *Tracker) Track(ctx context.Context, p social.Provider, account string, kind Kind) (Diff, error) {
+	snapshot, err := TakeProvider(ctx, p, account, kind)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	latest, err := t.store.Latest(snapshot.Provider, account, kind)
+	if err != nil && !errors.Is(err, socialErrors.ErrNotFound) {
+		return Diff{}, err
+	}
+	if err := t.store.Save(snapshot); err != nil {
+		return Diff{}, err
+	}
+	return Compare(latest, snapshot), nil
+}
@@ -0,0 +1,172 @@
+/*
+snapshot_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/social"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		older     *Snapshot
+		newer     *Snapshot
+		added     []string
+		removed   []string
+		unchanged []string
+	}{
+		{"no older snapshot", nil, newTestSnapshot("twitter", "", 2, "a", "b"), []string{"a", "b"}, []string{}, []string{}},
+		{"disjoint", newTestSnapshot("twitter", "", 1, "a", "b"), newTestSnapshot("twitter", "", 2, "c", "d"), []string{"c", "d"}, []string{"a", "b"}, []string{}},
+		{"overlapping", newTestSnapshot("twitter", "", 1, "a", "b", "c"), newTestSnapshot("twitter", "", 2, "b", "c", "d"), []string{"d"}, []string{"a"}, []string{"b", "c"}},
+		{"unchanged", newTestSnapshot("twitter", "", 1, "a", "b"), newTestSnapshot("twitter", "", 2, "a", "b"), []string{}, []string{}, []string{"a", "b"}},
+		{"empty newer", newTestSnapshot("twitter", "", 1, "a"), newTestSnapshot("twitter", "", 2), []string{}, []string{"a"}, []string{}},
+		// Snapshots built by hand may be unsorted and contain duplicates
+		{"duplicate ids", newTestSnapshot("twitter", "", 1, "b", "a", "a"), newTestSnapshot("twitter", "", 2, "c", "b", "c"), []string{"c"}, []string{"a"}, []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Compare(tt.older, tt.newer)
+			if !reflect.DeepEqual(diff.Added, tt.added) || !reflect.DeepEqual(diff.Removed, tt.removed) || !reflect.DeepEqual(diff.Unchanged, tt.unchanged) {
+				t.Errorf("Compare = added %v, removed %v, unchanged %v, want %v, %v, %v",
+					diff.Added, diff.Removed, diff.Unchanged, tt.added, tt.removed, tt.unchanged)
+			}
+			if diff.Changed() != (len(tt.added) > 0 || len(tt.removed) > 0) {
+				t.Errorf("Changed() = %v", diff.Changed())
+			}
+			if diff.To != tt.newer.TakenAt || (tt.older == nil && !diff.From.IsZero()) || (tt.older != nil && diff.From != tt.older.TakenAt) {
+				t.Errorf("From, To = %v, %v", diff.From, diff.To)
+			}
+		})
+	}
+}
+
+// fakeProvider is a Provider serving the given follower pages.
+type fakeProvider struct {
+	capabilities social.Capabilities
+	pages        [][]string
+	err          error
+}
+
+func (p *fakeProvider) Name() string                      { return "fake" }
+func (p *fakeProvider) Capabilities() social.Capabilities { return p.capabilities }
+func (p *fakeProvider) GoSocialUser() (*models.SocialUser, error) {
+	return nil, social.ErrUnsupported
+}
+func (p *fakeProvider) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	return nil, social.ErrUnsupported
+}
+func (p *fakeProvider) FollowersIterator() *social.Iterator[string] {
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		if p.err != nil {
+			return nil, "", p.err
+		}
+		page := len(cursor)
+		if page == len(p.pages)-1 {
+			return p.pages[page], "", nil
+		}
+		// The cursor of page n has length n
+		return p.pages[page], cursor + "x", nil
+	})
+}
+func (p *fakeProvider) FollowingIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+func (p *fakeProvider) RefreshContext(ctx context.Context) error { return social.ErrUnsupported }
+func (p *fakeProvider) RevokeContext(ctx context.Context) error  { return social.ErrUnsupported }
+
+func TestTake(t *testing.T) {
+	p := &fakeProvider{capabilities: social.CapFollowers, pages: [][]string{{"c", "a"}, {"b", "a"}}}
+
+	s, err := TakeProvider(context.Background(), p, "work", Followers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ids are sorted and duplicates of different pages removed
+	if !reflect.DeepEqual(s.IDs, []string{"a", "b", "c"}) || s.Provider != "fake" || s.Account != "work" || s.Kind != Followers || s.TakenAt.IsZero() {
+		t.Errorf("snapshot = %+v", s)
+	}
+	if !s.Contains("b") || s.Contains("d") {
+		t.Errorf("Contains(b), Contains(d) = %v, %v, want true, false", s.Contains("b"), s.Contains("d"))
+	}
+
+	tests := []struct {
+		name string
+		p    *fakeProvider
+		kind Kind
+		err  error
+	}{
+		{"unsupported followers", &fakeProvider{}, Followers, social.ErrUnsupported},
+		{"unsupported following", p, Following, social.ErrUnsupported},
+		{"unknown kind", p, Kind("likes"), socialErrors.ErrBadRequest},
+		{"iterator error", &fakeProvider{capabilities: social.CapFollowers, err: socialErrors.ErrRateLimit}, Followers, socialErrors.ErrRateLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, err := TakeProvider(context.Background(), tt.p, "", tt.kind); s != nil || !errors.Is(err, tt.err) {
+				t.Errorf("TakeProvider = %+v, %v, want %v", s, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTracker(t *testing.T) {
+	store := NewMemoryStore()
+	tracker := NewTracker(store)
+	p := &fakeProvider{capabilities: social.CapFollowers, pages: [][]string{{"a", "b"}}}
+
+	// The first snapshot is compared to an empty one, since the store returns ErrNotFound
+	diff, err := tracker.Track(context.Background(), p, "", Followers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(diff.Added, []string{"a", "b"}) || len(diff.Removed) != 0 || !diff.From.IsZero() {
+		t.Errorf("first diff = %+v, want a and b added", diff)
+	}
+
+	p.pages = [][]string{{"b"}, {"c"}}
+	diff, err = tracker.Track(context.Background(), p, "", Followers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(diff.Added, []string{"c"}) || !reflect.DeepEqual(diff.Removed, []string{"a"}) || diff.From.IsZero() {
+		t.Errorf("second diff = %+v, want c added and a removed", diff)
+	}
+	if latest, err := store.Latest("fake", "", Followers); err != nil || !reflect.DeepEqual(latest.IDs, []string{"b", "c"}) {
+		t.Errorf("latest snapshot = %+v, %v, want b and c", latest, err)
+	}
+
+	// Unsupported kinds are not saved
+	if _, err := tracker.Track(context.Background(), p, "", Following); !errors.Is(err, social.ErrUnsupported) {
+		t.Errorf("error = %v, want %v", err, social.ErrUnsupported)
+	}
+	if _, err := store.Latest("fake", "", Following); !errors.Is(err, socialErrors.ErrNotFound) {
+		t.Errorf("Latest of an unsupported kind = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		ids  []string
+		want []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"a"}, []string{"a"}},
+		{[]string{"b", "a", "b", "a"}, []string{"a", "b"}},
+		{[]string{"a", "a", "a"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		if got := unique(tt.ids); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("unique(%v) = %v, want %v", tt.ids, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+/*
+store.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emrearmagan/go-social/models/errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	storeFileMode = 0600
+	storeDirMode  = 0700
+)
+
+// Store persists the snapshots. Snapshots are keyed by the provider, the account name and the kind.
+type Store interface {
+	// Save stores the snapshot. Older snapshots of the same key are kept, unless they exceed the retention of the Store.
+	Save(snapshot *Snapshot) error
+	// Latest returns the most recent snapshot. Returns an ErrNotFound SocialError if no snapshot is stored.
+	Latest(provider, account string, kind Kind) (*Snapshot, error)
+	// List returns all stored snapshots, the oldest first.
+	List(provider, account string, kind Kind) ([]*Snapshot, error)
+}
+
+// storeKey returns the key under which the snapshots of the provider, account and kind are stored.
+func storeKey(provider, account string, kind Kind) string {
+	if account == "" {
+		return provider + "/" + string(kind)
+	}
+	return provider + "/" + account + "/" + string(kind)
+}
+
+// ErrSnapshotNotFound returns the error a Store returns if no snapshot is stored for the provider, account and kind.
+func ErrSnapshotNotFound(provider, account string, kind Kind) error {
+	return errors.New(errors.ErrNotFound, fmt.Sprintf("snapshot.Store: no snapshot stored for %s", storeKey(provider, account, kind)))
+}
+
+// StoreOption configures a MemoryStore or FileStore.
+type StoreOption func(*storeOptions)
+
+type storeOptions struct {
+	// retention is the number of snapshots kept per key, all if 0
+	retention int
+}
+
+// WithRetention keeps only the latest n snapshots of each provider, account and kind. Older snapshots are
+// deleted on Save. By default, all snapshots are kept.
+func WithRetention(n int) StoreOption {
+	return func(o *storeOptions) {
+		if n > 0 {
+			o.retention = n
+		}
+	}
+}
+
+func newStoreOptions(opts []StoreOption) storeOptions {
+	var o storeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// retain returns the latest snapshots according to the retention.
+func (o storeOptions) retain(snapshots []*Snapshot) []*Snapshot {
+	if o.retention > 0 && len(snapshots) > o.retention {
+		return append([]*Snapshot(nil), snapshots[len(snapshots)-o.retention:]...)
+	}
+	return snapshots
+}
+
+// insert inserts the snapshot into the snapshots ordered by TakenAt.
+func insert(snapshots []*Snapshot, snapshot *Snapshot) []*Snapshot {
+	i := sort.Search(len(snapshots), func(i int) bool {
+		return snapshots[i].TakenAt.After(snapshot.TakenAt)
+	})
+	snapshots = append(snapshots, nil)
+	copy(snapshots[i+1:], snapshots[i:])
+	snapshots[i] = snapshot
+	return snapshots
+}
+
+// clone returns a copy of the snapshot, so changes to it are not reflected in a Store.
+func clone(snapshot *Snapshot) *Snapshot {
+	c := *snapshot
+	c.IDs = append([]string(nil), snapshot.IDs...)
+	return &c
+}
+
+// MemoryStore is a Store keeping the snapshots in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[string][]*Snapshot
+	options   storeOptions
+}
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore(opts ...StoreOption) *MemoryStore {
+	return &MemoryStore{snapshots: make(map[string][]*Snapshot), options: newStoreOptions(opts)}
+}
+
+// Save stores a copy of the snapshot.
+func (s *MemoryStore) Save(snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := storeKey(snapshot.Provider, snapshot.Account, snapshot.Kind)
+	s.snapshots[key] = s.options.retain(insert(s.snapshots[key], clone(snapshot)))
+	return nil
+}
+
+// Latest returns the most recent snapshot.
+func (s *MemoryStore) Latest(provider, account string, kind Kind) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := s.snapshots[storeKey(provider, account, kind)]
+	if len(snapshots) == 0 {
+		return nil, ErrSnapshotNotFound(provider, account, kind)
+	}
+	return clone(snapshots[len(snapshots)-1]), nil
+}
+
+// List returns all stored snapshots, the oldest first.
+func (s *MemoryStore) List(provider, account string, kind Kind) ([]*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := s.snapshots[storeKey(provider, account, kind)]
+	list := make([]*Snapshot, len(snapshots))
+	for i, snapshot := range snapshots {
+		list[i] = clone(snapshot)
+	}
+	return list, nil
+}
+
+// FileStore is a Store saving the snapshots into a directory with one JSON file per provider, account and kind,
+// e.g. "twitter/work/followers.json" or "twitter/followers.json" without an account. Thus, a Save only
+// rewrites the snapshots of its key. Use WithRetention to limit the size of the files.
+// It is safe for concurrent use, but not for multiple processes writing the same directory.
+type FileStore struct {
+	dir     string
+	options storeOptions
+	mu      sync.Mutex
+}
+
+// NewFileStore returns a new FileStore for the given directory. The directory is created on the first Save.
+func NewFileStore(dir string, opts ...StoreOption) *FileStore {
+	return &FileStore{dir: dir, options: newStoreOptions(opts)}
+}
+
+// Save stores the snapshot and writes the file of its key.
+func (s *FileStore) Save(snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(snapshot.Provider, snapshot.Account, snapshot.Kind)
+	snapshots, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	return s.write(path, s.options.retain(insert(snapshots, snapshot)))
+}
+
+// Latest returns the most recent snapshot.
+func (s *FileStore) Latest(provider, account string, kind Kind) (*Snapshot, error) {
+	snapshots, err := s.List(provider, account, kind)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, ErrSnapshotNotFound(provider, account, kind)
+	}
+	return snapshots[len(snapshots)-1], nil
+}
+
+// List returns all stored snapshots, the oldest first.
+func (s *FileStore) List(provider, account string, kind Kind) ([]*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.path(provider, account, kind))
+}
+
+// path returns the file of the snapshots of the provider, account and kind.
+func (s *FileStore) path(provider, account string, kind Kind) string {
+	if account == "" {
+		return filepath.Join(s.dir, pathSegment(provider), pathSegment(string(kind))+".json")
+	}
+	return filepath.Join(s.dir, pathSegment(provider), pathSegment(account), pathSegment(string(kind))+".json")
+}
+
+// pathSegment escapes the given name, so it can be used as a single segment of a file path.
+func pathSegment(name string) string {
+	escaped := url.PathEscape(name)
+	switch {
+	case escaped == "":
+		// Escaped names never contain a single %
+		return "%"
+	case strings.Trim(escaped, ".") == "":
+		// "." and ".." would refer to the directory itself or its parent
+		return strings.Repeat("%2E", len(escaped))
+	}
+	return escaped
+}
+
+// read returns the snapshots of the file. A missing file has no snapshots.
+func (s *FileStore) read(path string) ([]*Snapshot, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &snapshots); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// write replaces the file atomically, so a crash does not leave a partially written file behind.
+func (s *FileStore) write(path string, snapshots []*Snapshot) error {
+	content, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), storeDirMode); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(storeFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
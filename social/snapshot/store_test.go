@@ -0,0 +1,133 @@
+/*
+store_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package snapshot
+
+import (
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestSnapshot(provider, account string, day int, ids ...string) *Snapshot {
+	return &Snapshot{
+		Provider: provider,
+		Account:  account,
+		Kind:     Followers,
+		IDs:      ids,
+		TakenAt:  time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(opts ...StoreOption) Store{
+		"memory": func(opts ...StoreOption) Store { return NewMemoryStore(opts...) },
+		"file":   func(opts ...StoreOption) Store { return NewFileStore(t.TempDir(), opts...) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			if _, err := store.Latest("twitter", "", Followers); !errors.Is(err, socialErrors.ErrNotFound) {
+				t.Errorf("Latest of an empty store = %v, want ErrNotFound", err)
+			}
+
+			// Saved out of order and for another account
+			for _, s := range []*Snapshot{
+				newTestSnapshot("twitter", "", 2, "b"),
+				newTestSnapshot("twitter", "", 1, "a"),
+				newTestSnapshot("twitter", "work", 3, "c"),
+			} {
+				if err := store.Save(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			list, err := store.List("twitter", "", Followers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []*Snapshot{newTestSnapshot("twitter", "", 1, "a"), newTestSnapshot("twitter", "", 2, "b")}
+			if !reflect.DeepEqual(list, want) {
+				t.Errorf("List = %+v, want %+v", list, want)
+			}
+
+			latest, err := store.Latest("twitter", "work", Followers)
+			if err != nil || latest.IDs[0] != "c" {
+				t.Errorf("Latest = %+v, %v, want the snapshot of the account", latest, err)
+			}
+		})
+
+		t.Run(name+" retention", func(t *testing.T) {
+			store := newStore(WithRetention(2))
+			for day := 1; day <= 4; day++ {
+				if err := store.Save(newTestSnapshot("github", "", day, "a")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			list, err := store.List("github", "", Followers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 2 || list[0].TakenAt.Day() != 3 || list[1].TakenAt.Day() != 4 {
+				t.Errorf("List = %+v, want the snapshots of day 3 and 4", list)
+			}
+		})
+	}
+}
+
+// TestFileStoreLayout checks that every provider, account and kind is stored in its own file
+// and that names cannot escape the directory.
+func TestFileStoreLayout(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	for _, s := range []*Snapshot{
+		newTestSnapshot("twitter", "", 1, "a"),
+		newTestSnapshot("twitter", "work", 1, "b"),
+		newTestSnapshot("github", "../..", 1, "c"),
+		newTestSnapshot("github", "a/b", 1, "d"),
+	} {
+		if err := store.Save(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, file := range []string{
+		"twitter/followers.json",
+		"twitter/work/followers.json",
+		"github/..%2F../followers.json",
+		"github/a%2Fb/followers.json",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+			t.Errorf("missing file %s: %v", file, err)
+		}
+	}
+
+	latest, err := store.Latest("github", "../..", Followers)
+	if err != nil || latest.IDs[0] != "c" {
+		t.Errorf("Latest = %+v, %v", latest, err)
+	}
+}
+
+func TestPathSegment(t *testing.T) {
+	tests := map[string]string{
+		"twitter": "twitter",
+		"":        "%",
+		".":       "%2E",
+		"..":      "%2E%2E",
+		"a/b":     "a%2Fb",
+		"../..":   "..%2F..",
+	}
+	for name, want := range tests {
+		if got := pathSegment(name); got != want {
+			t.Errorf("pathSegment(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ package spotify
 import (
 	"fmt"
 	"github.com/emrearmagan/go-social/models/errors"
+	"time"
 )
 
 // APIError represents a Spotify API error with its corresponding http StatusCode response
@@ -16,6 +17,10 @@ import (
 type APIError struct {
 	StatusCode int
 	Errors     ErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 // ErrorDetail represents the actual error response from the Api
@@ -36,16 +41,16 @@ func (e *APIError) Error() string {
 	if (e.Errors != ErrorDetail{}) {
 		return fmt.Sprintf("Spotify: %d - %v", e.Errors.ErrorStruct.StatusCode, e.Errors.ErrorStruct.Message)
 	}
+	if e.Body != "" {
+		return fmt.Sprintf("Spotify: %d - %v", e.StatusCode, e.Body)
+	}
 	return ""
 }
 
 // Empty returns true if empty. Otherwise, at least 1 error message/code is
 // present and false is returned.
 func (e *APIError) Empty() bool {
-	if (e.Errors == ErrorDetail{}) {
-		return true
-	}
-	return false
+	return e.Errors == ErrorDetail{} && e.Body == ""
 }
 
 func (e *APIError) Status() int {
@@ -56,24 +61,43 @@ func (e *APIError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *APIError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *APIError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 func (e *APIError) ReturnErrorResponse() error {
-	switch e.Status() {
-	case 304: // The content has not been modified and client should use cached data
-		return errors.New(errors.ErrNotModified, e.Error())
-	case 401, 403: // Invalid or expired token (if token has been revoked) - The access token used in the request is incorrect or has expired.
-		return errors.New(errors.ErrUnauthorized, e.Error())
-	case 429: // Rate limit exceeded	 - The request limit for this resource has been reached for the current rate limit window.
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case 500, 502, 503: //Internal api error
-		return errors.New(errors.ErrApiError, e.Error())
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// socialError maps the APIError onto a SocialError of the given kind.
+func (e *APIError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       "",
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
 	}
-	return errors.New(errors.ErrUnknownError, e.Error())
 }
 
 //RefreshError
 type RefreshError struct {
 	StatusCode int
 	Errors     RefreshDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 type RefreshDetail struct {
@@ -89,13 +113,16 @@ func (e *RefreshError) Error() string {
 	if (e.Errors != RefreshDetail{}) {
 		return fmt.Sprintf("Spotify: %d - %v : %v", e.StatusCode, e.Errors.Error, e.Errors.ErrorDescription)
 	}
+	if e.Body != "" {
+		return fmt.Sprintf("Spotify: %d - %v", e.StatusCode, e.Body)
+	}
 	return ""
 }
 
 // Empty returns true if empty. Otherwise, at least 1 error message/code is
 // present and false is returned.
 func (e *RefreshError) Empty() bool {
-	return e.Errors == RefreshDetail{}
+	return e.Errors == RefreshDetail{} && e.Body == ""
 }
 
 func (e *RefreshError) Status() int {
@@ -106,16 +133,35 @@ func (e *RefreshError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *RefreshError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *RefreshError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 func (e *RefreshError) ReturnErrorResponse() error {
-	switch e.Status() {
-	case 400:
-		return errors.New(errors.ErrBadRequest, e.Error())
-	case 401, 403: // Invalid or expired token (if token has been revoked) - The access token used in the request is incorrect or has expired.
-		return errors.New(errors.ErrUnauthorized, e.Error())
-	case 429: // Rate limit exceeded	 - The request limit for this resource has been reached for the current rate limit window.
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case 500, 502, 503: //Internal api error
-		return errors.New(errors.ErrApiError, e.Error())
+	switch e.Errors.Error {
+	case "invalid_grant", "invalid_client":
+		return e.socialError(errors.ErrBadAuthenticationData)
+	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// socialError maps the RefreshError onto a SocialError of the given kind.
+func (e *RefreshError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       e.Errors.Error,
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
 	}
-	return errors.New(errors.ErrUnknownError, e.Error())
 }
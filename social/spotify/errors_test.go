@@ -0,0 +1,75 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "expired token", Status: 401, ContentType: "application/json", Body: `{"error":{"status":401,"message":"The access token expired"}}`, Kind: socialErrors.ErrUnauthorized},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"error":{"status":403,"message":"Insufficient client scope"}}`, Kind: socialErrors.ErrForbidden},
+		{Name: "bad request", Status: 400, ContentType: "application/json", Body: `{"error":{"status":400,"message":"Only valid bearer authentication supported"}}`, Kind: socialErrors.ErrBadRequest},
+		{Name: "rate limit", Status: 429, ContentType: "application/json", Body: `{"error":{"status":429,"message":"API rate limit exceeded"}}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Retryable: true},
+		{Name: "server error", Status: 502, ContentType: "application/json", Body: `{"error":{"status":502,"message":"Bad gateway."}}`, Kind: socialErrors.ErrApiError, Retryable: true},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background())
+		return err
+	})
+}
+
+func TestRefreshErrorMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		kind      error
+		retryable bool
+	}{
+		{"invalid grant", 400, `{"error":"invalid_grant","error_description":"Invalid refresh token"}`, socialErrors.ErrBadAuthenticationData, false},
+		{"invalid client", 400, `{"error":"invalid_client","error_description":"Invalid client secret"}`, socialErrors.ErrBadAuthenticationData, false},
+		{"invalid request", 400, `{"error":"invalid_request","error_description":"refresh_token must be supplied"}`, socialErrors.ErrBadRequest, false},
+		{"server error", 503, `{"error":"server_error"}`, socialErrors.ErrApiError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiError := new(RefreshError)
+			if err := json.Unmarshal([]byte(tt.body), apiError.ErrorDetail()); err != nil {
+				t.Fatal(err)
+			}
+			apiError.SetStatus(tt.status)
+			err := apiError.ReturnErrorResponse()
+
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("error = %v, want kind %v", err, tt.kind)
+			}
+			var socialErr socialErrors.SocialError
+			if !errors.As(err, &socialErr) {
+				t.Fatalf("error %T is no SocialError", err)
+			}
+			if socialErr.Code != apiError.Errors.Error {
+				t.Errorf("Code = %q, want %q", socialErr.Code, apiError.Errors.Error)
+			}
+			if got := socialErrors.IsRetryable(err); got != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
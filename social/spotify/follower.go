@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package spotify
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 )
@@ -31,48 +32,78 @@ func newFollowerService(oauth2 *oauth2.OAuth2) *FollowerService {
 
 // Following returns information about the authenticated users followers.
 func (f *FollowerService) Following(params *FollowingParams) (*FollowingResponse, error) {
+	return f.FollowingContext(f.oauth2.Context(), params)
+}
+
+// FollowingContext is like Following, but sends the request with the given context.
+func (f *FollowerService) FollowingContext(ctx context.Context, params *FollowingParams) (*FollowingResponse, error) {
 	followed := new(FollowingResponse)
 	apiError := new(APIError)
 
-	err := f.oauth2.Get(FollowerPath, followed, apiError, params)
+	err := f.oauth2.WithContext(ctx).Get(FollowerPath, followed, apiError, params)
 	return followed, social.CheckError(err)
 }
 
+// FollowingIterator returns an Iterator over all artists the authenticated user follows.
+// The After cursor of the given params is used as the starting point. The Type defaults to artist.
+func (f *FollowerService) FollowingIterator(params *FollowingParams) *social.Iterator[Artist] {
+	p := FollowingParams{}
+	if params != nil {
+		p = *params
+	}
+	if p.Type == "" {
+		p.Type = "artist"
+	}
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]Artist, string, error) {
+		if cursor != "" {
+			p.After = cursor
+		}
+		resp, err := f.FollowingContext(ctx, &p)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Artists.Items, resp.Artists.Cursors.After, nil
+	})
+}
+
 type FollowingParams struct {
 	Type  string `url:"type,omitempty"`  // The ID type: currently only artist is supported.
-	After int    `url:"after,omitempty"` // The last artist ID retrieved from the previous request.
+	After string `url:"after,omitempty"` // The last artist ID retrieved from the previous request.
 	Limit int    `url:"limit,omitempty"` // The maximum number of items to return. Default: 20. Minimum: 1. Maximum: 50.
 }
 
 type FollowingResponse struct {
 	Artists struct {
-		Items []struct {
-			ExternalUrls struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Followers struct {
-				Href  interface{} `json:"href"`
-				Total int         `json:"total"`
-			} `json:"followers"`
-			Genres []string `json:"genres"`
-			Href   string   `json:"href"`
-			ID     string   `json:"id"`
-			Images []struct {
-				Height int    `json:"height"`
-				URL    string `json:"url"`
-				Width  int    `json:"width"`
-			} `json:"images"`
-			Name       string `json:"name"`
-			Popularity int    `json:"popularity"`
-			Type       string `json:"type"`
-			URI        string `json:"uri"`
-		} `json:"items"`
+		Items   []Artist    `json:"items"`
 		Next    interface{} `json:"next"`
 		Total   int         `json:"total"`
 		Cursors struct {
-			After interface{} `json:"after"`
+			After string `json:"after"`
 		} `json:"cursors"`
 		Limit int    `json:"limit"`
 		Href  string `json:"href"`
 	} `json:"artists"`
 }
+
+// Artist represents a followed Spotify artist.
+type Artist struct {
+	ExternalUrls struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Followers struct {
+		Href  interface{} `json:"href"`
+		Total int         `json:"total"`
+	} `json:"followers"`
+	Genres []string `json:"genres"`
+	Href   string   `json:"href"`
+	ID     string   `json:"id"`
+	Images []struct {
+		Height int    `json:"height"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+	} `json:"images"`
+	Name       string `json:"name"`
+	Popularity int    `json:"popularity"`
+	Type       string `json:"type"`
+	URI        string `json:"uri"`
+}
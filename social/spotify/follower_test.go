@@ -0,0 +1,61 @@
+/*
+follower_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package spotify
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestFollowingIterator checks that the iterator follows the after cursor and requests artists by default.
+func TestFollowingIterator(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != FollowerPath || r.URL.Query().Get("type") != "artist" {
+			http.Error(w, `{"error":{"status":400,"message":"Bad Request"}}`, http.StatusBadRequest)
+			return
+		}
+		after := r.URL.Query().Get("after")
+		cursors = append(cursors, after)
+		w.Header().Set("Content-Type", "application/json")
+		switch after {
+		case "":
+			w.Write([]byte(`{"artists":{"items":[{"id":"a"},{"id":"b"}],"cursors":{"after":"b"}}}`))
+		default:
+			w.Write([]byte(`{"artists":{"items":[{"id":"c"}],"cursors":{"after":null}}}`))
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+
+	for _, params := range []*FollowingParams{nil, {Limit: 2}} {
+		cursors = nil
+		artists, err := c.Follower.FollowingIterator(params).All(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+		for _, a := range artists {
+			ids = append(ids, a.ID)
+		}
+		if !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+			t.Errorf("params %+v: artists = %v, want [a b c]", params, ids)
+		}
+		if !reflect.DeepEqual(cursors, []string{"", "b"}) {
+			t.Errorf("params %+v: requested cursors %q, want [\"\" \"b\"]", params, cursors)
+		}
+	}
+}
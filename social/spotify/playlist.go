@@ -7,8 +7,10 @@ Copyright © go-social. All rights reserved.
 package spotify
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
+	"strconv"
 )
 
 const (
@@ -30,58 +32,92 @@ func newPlaylistService(oauth2 *oauth2.OAuth2) *PlaylistService {
 // UserPlaylists returns the playlists for the authenticated user.
 // https://developer.spotify.com/console/get-current-user/
 func (p *PlaylistService) UserPlaylists(params *UserPlaylistParams) (*Playlist, error) {
+	return p.UserPlaylistsContext(p.oauth2.Context(), params)
+}
+
+// UserPlaylistsContext is like UserPlaylists, but sends the request with the given context.
+func (p *PlaylistService) UserPlaylistsContext(ctx context.Context, params *UserPlaylistParams) (*Playlist, error) {
 	playlist := new(Playlist)
 	apiError := new(APIError)
 
-	err := p.oauth2.Get(PlaylistPath, playlist, apiError, params)
+	err := p.oauth2.WithContext(ctx).Get(PlaylistPath, playlist, apiError, params)
 	return playlist, social.CheckError(err)
 }
 
+// UserPlaylistsIterator returns an Iterator over all playlists of the authenticated user.
+// The Offset of the given params is used as the starting point.
+func (p *PlaylistService) UserPlaylistsIterator(params *UserPlaylistParams) *social.Iterator[PlaylistItem] {
+	pp := UserPlaylistParams{}
+	if params != nil {
+		pp = *params
+	}
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]PlaylistItem, string, error) {
+		if cursor != "" {
+			offset, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			pp.Offset = offset
+		}
+		resp, err := p.UserPlaylistsContext(ctx, &pp)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.Next == nil || len(resp.Items) == 0 {
+			return resp.Items, "", nil
+		}
+		return resp.Items, strconv.Itoa(resp.Offset + len(resp.Items)), nil
+	})
+}
+
 // UserPlaylistParams are the params for the Playlist endpoint.
 type UserPlaylistParams struct {
 	Limit  int `url:"limit,omitempty"`  // The maximum number of playlists to return. Default: 20. Minimum: 1. Maximum: 50.’
-	offset int `url:"offset,omitempty"` // The index of the first playlist to return. Default: 0 (the first object). Maximum offset: 100.000. Use with limit to get the next set of playlists.’
+	Offset int `url:"offset,omitempty"` // The index of the first playlist to return. Default: 0 (the first object). Maximum offset: 100.000. Use with limit to get the next set of playlists.’
 }
 
 type Playlist struct {
-	Href  string `json:"href"`
-	Items []struct {
-		Collaborative bool   `json:"collaborative"`
-		Description   string `json:"description"`
-		ExternalUrls  struct {
+	Href     string         `json:"href"`
+	Items    []PlaylistItem `json:"items"`
+	Limit    int            `json:"limit"`
+	Next     interface{}    `json:"next"`
+	Offset   int            `json:"offset"`
+	Previous interface{}    `json:"previous"`
+	Total    int            `json:"total"`
+}
+
+// PlaylistItem represents a single playlist of the user.
+type PlaylistItem struct {
+	Collaborative bool   `json:"collaborative"`
+	Description   string `json:"description"`
+	ExternalUrls  struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Href   string `json:"href"`
+	ID     string `json:"id"`
+	Images []struct {
+		Height int    `json:"height"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+	} `json:"images"`
+	Name  string `json:"name"`
+	Owner struct {
+		DisplayName  string `json:"display_name"`
+		ExternalUrls struct {
 			Spotify string `json:"spotify"`
 		} `json:"external_urls"`
-		Href   string `json:"href"`
-		ID     string `json:"id"`
-		Images []struct {
-			Height int    `json:"height"`
-			URL    string `json:"url"`
-			Width  int    `json:"width"`
-		} `json:"images"`
-		Name  string `json:"name"`
-		Owner struct {
-			DisplayName  string `json:"display_name"`
-			ExternalUrls struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href string `json:"href"`
-			ID   string `json:"id"`
-			Type string `json:"type"`
-			URI  string `json:"uri"`
-		} `json:"owner"`
-		PrimaryColor interface{} `json:"primary_color"`
-		Public       bool        `json:"public"`
-		SnapshotID   string      `json:"snapshot_id"`
-		Tracks       struct {
-			Href  string `json:"href"`
-			Total int    `json:"total"`
-		} `json:"tracks"`
+		Href string `json:"href"`
+		ID   string `json:"id"`
 		Type string `json:"type"`
 		URI  string `json:"uri"`
-	} `json:"items"`
-	Limit    int         `json:"limit"`
-	Next     interface{} `json:"next"`
-	Offset   int         `json:"offset"`
-	Previous interface{} `json:"previous"`
-	Total    int         `json:"total"`
+	} `json:"owner"`
+	PrimaryColor interface{} `json:"primary_color"`
+	Public       bool        `json:"public"`
+	SnapshotID   string      `json:"snapshot_id"`
+	Tracks       struct {
+		Href  string `json:"href"`
+		Total int    `json:"total"`
+	} `json:"tracks"`
+	Type string `json:"type"`
+	URI  string `json:"uri"`
 }
@@ -0,0 +1,60 @@
+/*
+playlist_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package spotify
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestUserPlaylistsIterator checks that the iterator advances the offset by the number of returned playlists
+// until next is null.
+func TestUserPlaylistsIterator(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != PlaylistPath || r.URL.Query().Get("limit") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		w.Header().Set("Content-Type", "application/json")
+		switch offset {
+		case "":
+			w.Write([]byte(`{"items":[{"id":"a"},{"id":"b"}],"limit":2,"offset":0,"next":"https://api.spotify.com/v1/me/playlists?offset=2&limit=2"}`))
+		case "2":
+			w.Write([]byte(`{"items":[{"id":"c"}],"limit":2,"offset":2,"next":null}`))
+		default:
+			w.Write([]byte(`{"items":[],"limit":2,"offset":3,"next":null}`))
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	playlists, err := c.Playlist.UserPlaylistsIterator(&UserPlaylistParams{Limit: 2}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, p := range playlists {
+		ids = append(ids, p.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+		t.Errorf("playlists = %v, want [a b c]", ids)
+	}
+	if !reflect.DeepEqual(offsets, []string{"", "2"}) {
+		t.Errorf("requested offsets %q, want [\"\" \"2\"]", offsets)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package spotify
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social"
+)
+
+// Client implements social.Provider.
+var _ social.Provider = (*Client)(nil)
+
+func init() {
+	social.Register(Name, newProvider)
+}
+
+// newProvider builds a Client for the account. The token of the account must be a *oauth2.Token.
+func newProvider(ctx context.Context, account social.Account) (social.Provider, error) {
+	token, ok := account.Token.(*oauth2.Token)
+	if !ok {
+		return nil, social.TokenTypeError(Name, account.Token)
+	}
+	return NewClient(ctx, &account.Credentials, token, account.Options...), nil
+}
+
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return Name
+}
+
+// Capabilities returns the supported methods of the Provider. The followers of a Spotify user cannot be listed.
+func (c *Client) Capabilities() social.Capabilities {
+	return social.CapFollowing | social.CapRefresh
+}
+
+// FollowersIterator returns an Iterator failing with social.ErrUnsupported.
+func (c *Client) FollowersIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+
+// FollowingIterator returns an Iterator over the ids of the artists the authenticated user follows.
+func (c *Client) FollowingIterator() *social.Iterator[string] {
+	return social.MapIterator(c.Follower.FollowingIterator(nil), func(a Artist) string {
+		return a.ID
+	})
+}
+
+// RefreshContext refreshes the token, see RefreshTokenContext.
+func (c *Client) RefreshContext(ctx context.Context) error {
+	_, err := c.RefreshTokenContext(ctx)
+	return err
+}
+
+// RevokeContext returns social.ErrUnsupported.
+func (c *Client) RevokeContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
@@ -14,15 +14,27 @@ import (
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
 	"strings"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "spotify"
+
 	Base = "https://api.spotify.com/"
 
 	RefreshBase = "https://accounts.spotify.com"
 	RefreshPath = "/api/token/"
 )
 
+// Endpoint is Spotify's OAuth2 endpoint.
+// https://developer.spotify.com/documentation/general/guides/authorization/code-flow/
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://accounts.spotify.com/authorize",
+	TokenURL:  "https://accounts.spotify.com/api/token",
+	AuthStyle: oauth2.AuthStyleInHeader,
+}
+
 type Client struct {
 	oauth2 *oauth2.OAuth2
 
@@ -32,37 +44,82 @@ type Client struct {
 }
 
 // NewClient returns a new Spotify Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token) *Client {
-	cl := client.NewHttpClient().Base(Base)
-	auther := oauth2.NewOAuth(ctx, c, token, cl)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
 
-	return &Client{
+	cli := &Client{
 		oauth2:   auther,
 		User:     newUserService(auther),
 		Playlist: newPlaylistService(auther),
 		Follower: newFollowerService(auther),
 	}
+
+	// Refresh the token transparently once the API rejects it
+	auther.SetRefreshTokenSource(oauth2.TokenSourceFunc(cli.refreshedToken))
+	return cli
+}
+
+// OnTokenRefresh sets a callback which is called with the new token once it has been refreshed,
+// automatically or with RefreshToken, e.g. for persisting the token.
+func (c *Client) OnTokenRefresh(fn func(token *oauth2.Token)) {
+	c.oauth2.OnTokenRefresh(fn)
+}
+
+// SetTokenStore sets the TokenStore refreshed tokens are saved into. The tokens are keyed by Name and the given account.
+func (c *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	c.oauth2.SetTokenStore(store, Name, account)
+}
+
+// refreshedToken refreshes the token with the context of the rejected request and returns it.
+// Used as TokenSource of the client.
+func (c *Client) refreshedToken(ctx context.Context) (*oauth2.Token, error) {
+	if _, err := c.RefreshTokenContext(ctx); err != nil {
+		return nil, err
+	}
+	return c.oauth2.Token(), nil
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (c *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return c.oauth2.RateLimit(endpoint)
 }
 
 // RefreshToken a new access token can be generated by supplying the refresh token originally obtained during
 // authorization code exchange.
 // https://developer.spotify.com/documentation/general/guides/authorization-guide/
 func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
+	return c.RefreshTokenContext(c.oauth2.Context())
+}
+
+// RefreshTokenContext is like RefreshToken, but sends the request with the given context.
+func (c *Client) RefreshTokenContext(ctx context.Context) (*oauth2.OAuthRefreshResponse, error) {
 	oauthResp := new(OAuth2Response)
 	apiError := new(RefreshError)
 
 	// Spotify requires the basic authentication for refreshing a token, but the bearer authentication for everything else. Yes I don't get it either
-	a := c.oauth2.New().Basic()
+	a := c.oauth2.WithContext(ctx).Basic()
 	err := a.RefreshToken(RefreshBase, RefreshPath, oauthResp, apiError)
-	c.oauth2.UpdateToken(oauth2.NewToken(oauthResp.AccessToken, c.oauth2.Token().RefreshToken))
+	// Keep the current refresh token if the provider did not issue a new one
+	refreshToken := oauthResp.RefreshToken
+	if refreshToken == "" {
+		refreshToken = c.oauth2.Token().RefreshToken
+	}
+	token := &oauth2.Token{
+		Token:        oauthResp.AccessToken,
+		RefreshToken: refreshToken,
+		TokenType:    oauthResp.TokenType,
+		Expiry:       oauth2.ExpiresIn(oauthResp.ExpiresIn),
+		Scopes:       strings.Fields(oauthResp.Scope),
+	}
+	if err == nil {
+		err = c.oauth2.UpdateToken(token)
+	}
 	return &oauth2.OAuthRefreshResponse{
-		Token: oauth2.Token{
-			Token:        oauthResp.AccessToken,
-			RefreshToken: c.oauth2.Token().RefreshToken,
-		},
+		Token:     *token,
 		TokenType: oauthResp.TokenType,
 		ExpiresIn: oauthResp.ExpiresIn,
-		Scope:     strings.Fields(oauthResp.Scope),
+		Scope:     token.Scopes,
 	}, social.CheckError(err)
 }
 
@@ -71,22 +128,29 @@ type OAuth2Response struct {
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int    `json:"expires_in"`
 	Scope       string `json:"scope"`
+	// RefreshToken is only set if Spotify issued a new refresh token, which replaces the current one
+	RefreshToken string `json:"refresh_token"`
 }
 
 func (s *Client) GoSocialUser() (*models.SocialUser, error) {
-	p, err := s.Playlist.UserPlaylists(nil)
+	return s.GoSocialUserContext(s.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (s *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	p, err := s.Playlist.UserPlaylistsContext(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	f, err := s.Follower.Following(&FollowingParams{
+	f, err := s.Follower.FollowingContext(ctx, &FollowingParams{
 		Type: "artist",
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := s.User.UserCredentials()
+	u, err := s.User.UserCredentialsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +160,7 @@ func (s *Client) GoSocialUser() (*models.SocialUser, error) {
 		avatarUrl = u.Images[0].URL
 	}
 	goSocial := models.SocialUser{
+		Provider:     Name,
 		Username:     u.DisplayName,
 		Name:         u.DisplayName,
 		UserId:       u.ID,
@@ -105,6 +170,9 @@ func (s *Client) GoSocialUser() (*models.SocialUser, error) {
 		AvatarUrl:    avatarUrl,
 		Followers:    u.Followers.Total,
 		Url:          u.ExternalUrls.Spotify,
+		Location:     u.Country,
+		Email:        u.Email,
+		FetchedAt:    time.Now(),
 	}
 
 	return &goSocial, nil
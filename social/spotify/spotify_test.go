@@ -0,0 +1,101 @@
+/*
+spotify_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package spotify
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handlerTransport serves all requests with the handler, regardless of their host.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"new refresh token", `{"access_token":"new-access","token_type":"Bearer","expires_in":3600,"refresh_token":"new-refresh"}`, "new-refresh"},
+		{"no refresh token", `{"access_token":"new-access","token_type":"Bearer","expires_in":3600}`, "old-refresh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Host+r.URL.Path != "accounts.spotify.com"+RefreshPath {
+					http.NotFound(w, r)
+					return
+				}
+				if err := r.ParseForm(); err != nil || r.PostForm.Get("refresh_token") != "old-refresh" {
+					http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			})
+
+			cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+			c := NewClient(context.Background(), cred, oauth2.NewToken("old-access", "old-refresh"),
+				client.WithTransport(handlerTransport{handler: handler}))
+			var notified *oauth2.Token
+			c.OnTokenRefresh(func(token *oauth2.Token) {
+				notified = token
+			})
+
+			resp, err := c.RefreshToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Token.Token != "new-access" || resp.Token.RefreshToken != tt.want {
+				t.Errorf("token = %q/%q, want new-access/%q", resp.Token.Token, resp.Token.RefreshToken, tt.want)
+			}
+			if c.oauth2.Token().RefreshToken != tt.want {
+				t.Errorf("client refresh token = %q, want %q", c.oauth2.Token().RefreshToken, tt.want)
+			}
+			if notified == nil || notified.RefreshToken != tt.want {
+				t.Errorf("OnTokenRefresh not called with the new token: %+v", notified)
+			}
+		})
+	}
+}
+
+// TestRefreshTokenBaseURL checks that client.WithBaseURL also redirects the refresh, which uses RefreshBase.
+func TestRefreshTokenBaseURL(t *testing.T) {
+	var refreshed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RefreshPath {
+			http.NotFound(w, r)
+			return
+		}
+		refreshed = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new-access","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("old-access", "old-refresh"), client.WithBaseURL(server.URL))
+	if _, err := c.RefreshTokenContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !refreshed || c.oauth2.Token().Token != "new-access" {
+		t.Errorf("refreshed = %v, token = %q, want the token of the httptest server", refreshed, c.oauth2.Token().Token)
+	}
+}
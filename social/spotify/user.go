@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package spotify
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 )
@@ -30,16 +31,22 @@ func newUserService(oauth2 *oauth2.OAuth2) *UserService {
 // UserCredentials returns the user credentials for the authenticated user.
 // https://developer.spotify.com/console/get-current-user/
 func (u *UserService) UserCredentials() (*User, error) {
+	return u.UserCredentialsContext(u.oauth2.Context())
+}
+
+// UserCredentialsContext is like UserCredentials, but sends the request with the given context.
+func (u *UserService) UserCredentialsContext(ctx context.Context) (*User, error) {
 	user := new(User)
 	apiError := new(APIError)
 
-	err := u.oauth2.Get(UserPath, user, apiError, nil)
+	err := u.oauth2.WithContext(ctx).Get(UserPath, user, apiError, nil)
 	return user, social.CheckError(err)
 }
 
 type User struct {
 	Country         string `json:"country"`
 	DisplayName     string `json:"display_name"`
+	Email           string `json:"email"` // Requires the user-read-email scope
 	ExplicitContent struct {
 		FilterEnabled bool `json:"filter_enabled"`
 		FilterLocked  bool `json:"filter_locked"`
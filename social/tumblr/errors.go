@@ -9,6 +9,8 @@ package tumblr
 import (
 	"fmt"
 	"github.com/emrearmagan/go-social/models/errors"
+	"strconv"
+	"time"
 )
 
 // APIError represents a Tumblr API StatusCode response
@@ -16,6 +18,10 @@ import (
 type APIError struct {
 	StatusCode int
 	Errors     ErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 // ErrorDetail represents an individual item in an APIError.
@@ -42,16 +48,19 @@ func (e *APIError) Error() string {
 		err := e.Errors.Errors[0]
 		return fmt.Sprintf("Tumblr: %d - %v", err.Code, err.Detail)
 	}
+	if e.Errors.Meta.Msg != "" {
+		return fmt.Sprintf("Tumblr: %d - %v", e.Errors.Meta.Status, e.Errors.Meta.Msg)
+	}
+	if e.Body != "" {
+		return fmt.Sprintf("Tumblr: %d - %v", e.StatusCode, e.Body)
+	}
 	return ""
 }
 
 // Empty returns true if empty. Otherwise, at least 1 error message/code is
-// present and false is returned.
+// present and false is returned. Some error responses only carry the meta.
 func (e *APIError) Empty() bool {
-	if len(e.Errors.Errors) == 0 {
-		return true
-	}
-	return false
+	return len(e.Errors.Errors) == 0 && e.Errors.Meta.Msg == "" && e.Body == ""
 }
 
 func (e *APIError) Status() int {
@@ -62,20 +71,43 @@ func (e *APIError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *APIError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *APIError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 //TODO common error response, couldnt find propper documentation for error codes
 func (e *APIError) ReturnErrorResponse() error {
-	switch e.Status() {
-	case 200:
+	if e.Status() == 200 {
 		return nil
-	case 304: // The content has not been modified and client should use cached data
-		return errors.New(errors.ErrNotModified, e.Error())
-	case 401, 403: // Invalid or expired token or client is not permitted to perform this action.
-		return errors.New(errors.ErrUnauthorized, e.Error())
-	case 429: // Rate limit exceeded	 - The request limit for this resource has been reached for the current rate limit window.
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case 500, 502, 503: //Internal api error
-		return errors.New(errors.ErrApiError, e.Error())
 	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
 
-	return errors.New(errors.ErrUnknownError, e.Error())
+// socialError maps the APIError onto a SocialError of the given kind.
+func (e *APIError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       e.code(),
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
+	}
+}
+
+// code returns the code of the first error, if any.
+func (e *APIError) code() string {
+	if len(e.Errors.Errors) == 0 {
+		return ""
+	}
+	return strconv.Itoa(e.Errors.Errors[0].Code)
 }
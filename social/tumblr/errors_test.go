@@ -0,0 +1,33 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package tumblr
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "unauthorized", Status: 401, ContentType: "application/json", Body: `{"meta":{"status":401,"msg":"Unauthorized"},"response":[],"errors":[{"title":"Unauthorized","code":1016,"detail":"Unable to authorize"}]}`, Kind: socialErrors.ErrUnauthorized, Code: "1016"},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"meta":{"status":403,"msg":"Forbidden"},"response":[],"errors":[{"title":"Forbidden","code":0,"detail":"Forbidden"}]}`, Kind: socialErrors.ErrForbidden, Code: "0"},
+		{Name: "not found", Status: 404, ContentType: "application/json", Body: `{"meta":{"status":404,"msg":"Not Found"},"response":[]}`, Kind: socialErrors.ErrNotFound},
+		{Name: "rate limit", Status: 429, ContentType: "application/json", Body: `{"meta":{"status":429,"msg":"Limit Exceeded"},"response":[]}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Retryable: true},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth1.NewToken("token", "secret"), client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background())
+		return err
+	})
+}
@@ -0,0 +1,59 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package tumblr
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social"
+)
+
+// Client implements social.Provider.
+var _ social.Provider = (*Client)(nil)
+
+func init() {
+	social.Register(Name, newProvider)
+}
+
+// newProvider builds a Client for the account. The token of the account must be a *oauth1.Token.
+func newProvider(ctx context.Context, account social.Account) (social.Provider, error) {
+	token, ok := account.Token.(*oauth1.Token)
+	if !ok {
+		return nil, social.TokenTypeError(Name, account.Token)
+	}
+	return NewClient(ctx, &account.Credentials, token, account.Options...), nil
+}
+
+// Name returns the provider name.
+func (s *Client) Name() string {
+	return Name
+}
+
+// Capabilities returns the supported methods of the Provider. Only GoSocialUser is implemented for Tumblr.
+func (s *Client) Capabilities() social.Capabilities {
+	return 0
+}
+
+// FollowersIterator returns an Iterator failing with social.ErrUnsupported.
+func (s *Client) FollowersIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+
+// FollowingIterator returns an Iterator failing with social.ErrUnsupported.
+func (s *Client) FollowingIterator() *social.Iterator[string] {
+	return social.UnsupportedIterator[string]()
+}
+
+// RefreshContext returns social.ErrUnsupported.
+func (s *Client) RefreshContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
+
+// RevokeContext returns social.ErrUnsupported.
+func (s *Client) RevokeContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
@@ -12,27 +12,90 @@ import (
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/social/client"
+	"net/url"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "tumblr"
+
 	Base = "https://api.tumblr.com/"
 )
 
+// Endpoint is Tumblr's OAuth1 endpoint.
+// https://www.tumblr.com/docs/en/api/v2#oauth1-authorization
+var Endpoint = oauth1.Endpoint{
+	RequestTokenURL: "https://www.tumblr.com/oauth/request_token",
+	AuthorizeURL:    "https://www.tumblr.com/oauth/authorize",
+	AccessTokenURL:  "https://www.tumblr.com/oauth/access_token",
+}
+
 type Client struct {
+	oauth1 *oauth1.OAuth1
+
 	User *UserService
 }
 
 // NewClient returns a new Spotify Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth1.Token) *Client {
-	cl := client.NewHttpClient().Base(Base)
-	auther := oauth1.NewOAuth(ctx, c, token, cl)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth1.Token, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
+	auther := oauth1.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
 	return &Client{
-		User: newUserService(auther),
+		oauth1: auther,
+		User:   newUserService(auther),
 	}
 }
 
+// SetTokenStore sets the TokenStore the token credentials obtained by AccessToken are saved into.
+// The tokens are keyed by Name and the given account.
+func (s *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	s.oauth1.SetTokenStore(store, Name, account)
+}
+
+// Token returns the token credentials the requests are signed with.
+func (s *Client) Token() *oauth1.Token {
+	return s.oauth1.Token()
+}
+
+// RequestToken obtains a request token for the three-legged flow of the Endpoint. See oauth1.OAuth1.RequestToken.
+func (s *Client) RequestToken(callbackURL string) (requestToken, requestSecret string, err error) {
+	return s.RequestTokenContext(s.oauth1.Context(), callbackURL)
+}
+
+// RequestTokenContext is like RequestToken, but sends the request with the given context.
+func (s *Client) RequestTokenContext(ctx context.Context, callbackURL string) (requestToken, requestSecret string, err error) {
+	return s.oauth1.WithContext(ctx).RequestToken(callbackURL)
+}
+
+// AuthorizationURL returns the url the user must be redirected to for authorizing the request token.
+func (s *Client) AuthorizationURL(requestToken string) (*url.URL, error) {
+	return s.oauth1.AuthorizationURL(requestToken)
+}
+
+// AccessToken exchanges the authorized request token for token credentials. The token credentials are used
+// by all services of the client and saved into the TokenStore, if any. See oauth1.OAuth1.AccessToken.
+func (s *Client) AccessToken(requestToken, requestSecret, verifier string) (*oauth1.Token, error) {
+	return s.AccessTokenContext(s.oauth1.Context(), requestToken, requestSecret, verifier)
+}
+
+// AccessTokenContext is like AccessToken, but sends the request with the given context.
+func (s *Client) AccessTokenContext(ctx context.Context, requestToken, requestSecret, verifier string) (*oauth1.Token, error) {
+	return s.oauth1.WithContext(ctx).AccessToken(requestToken, requestSecret, verifier)
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (s *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return s.oauth1.RateLimit(endpoint)
+}
+
 func (s *Client) GoSocialUser() (*models.SocialUser, error) {
-	u, err := s.User.UserCredentials()
+	return s.GoSocialUserContext(s.oauth1.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (s *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	u, err := s.User.UserCredentialsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +108,7 @@ func (s *Client) GoSocialUser() (*models.SocialUser, error) {
 	}
 
 	goSocial := models.SocialUser{
+		Provider: Name,
 		Username: u.Response.User.Name,
 		Name:     u.Response.User.Name,
 		//Does not provide a user id ?
@@ -55,6 +119,10 @@ func (s *Client) GoSocialUser() (*models.SocialUser, error) {
 		Following:    &u.Response.User.Following,
 		Followers:    follower,
 		Url:          u.Response.User.Blogs[0].URL,
+		// The primary blog is the first one
+		Bio:       u.Response.User.Blogs[0].Description,
+		BannerUrl: u.Response.User.Blogs[0].Theme.HeaderImage,
+		FetchedAt: time.Now(),
 	}
 
 	return &goSocial, nil
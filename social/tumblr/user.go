@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package tumblr
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/social"
 )
@@ -30,10 +31,15 @@ func newUserService(oauth1 *oauth1.OAuth1) *UserService {
 // UserCredentials returns the authorized user if credentials are valid and returns an error otherwise.
 // https://www.tumblr.com/docs/en/api/v2#userinfo--get-a-users-information
 func (u *UserService) UserCredentials() (*User, error) {
+	return u.UserCredentialsContext(u.oauth1.Context())
+}
+
+// UserCredentialsContext is like UserCredentials, but sends the request with the given context.
+func (u *UserService) UserCredentialsContext(ctx context.Context) (*User, error) {
 	user := new(User)
 	apiError := new(APIError)
 
-	err := u.oauth1.Get(UserPath, user, apiError, nil)
+	err := u.oauth1.WithContext(ctx).Get(UserPath, user, apiError, nil)
 	return user, social.CheckError(err)
 }
 
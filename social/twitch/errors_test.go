@@ -0,0 +1,33 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitch
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "invalid token", Status: 401, ContentType: "application/json", Body: `{"error":"Unauthorized","status":401,"message":"Invalid OAuth token"}`, Kind: socialErrors.ErrUnauthorized, Code: "Unauthorized"},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"error":"Forbidden","status":403,"message":"Missing scope"}`, Kind: socialErrors.ErrForbidden, Code: "Forbidden"},
+		{Name: "bad request", Status: 400, ContentType: "application/json", Body: `{"error":"Bad Request","status":400,"message":"Malformed query params."}`, Kind: socialErrors.ErrBadRequest, Code: "Bad Request"},
+		{Name: "too many requests", Status: 429, ContentType: "application/json", Body: `{"error":"Too Many Requests","status":429,"message":"Rate limit exceeded"}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Code: "Too Many Requests", Retryable: true},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background(), nil)
+		return err
+	})
+}
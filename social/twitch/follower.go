@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package twitch
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"time"
@@ -32,20 +33,40 @@ func newFollowerService(oauth2 *oauth2.OAuth2) *FollowerService {
 // https://dev.twitch.tv/docs/api/reference#get-users-follows
 // Required scopes: -
 func (s *FollowerService) GetFollower(params FollowerParams) (*FollowerResp, error) {
+	return s.GetFollowerContext(s.oauth2.Context(), params)
+}
+
+// GetFollowerContext is like GetFollower, but sends the request with the given context.
+func (s *FollowerService) GetFollowerContext(ctx context.Context, params FollowerParams) (*FollowerResp, error) {
 	subs := new(FollowerResp)
 	apiError := new(APIError)
 
-	err := s.oauth2.Get(FollowerPath, subs, apiError, params)
+	err := s.oauth2.WithContext(ctx).Get(FollowerPath, subs, apiError, params)
 	return subs, social.CheckError(err)
 }
 
+// FollowerIterator returns an Iterator over all follow relationships matching the given params.
+// The After cursor of the given params is used as the starting point.
+func (s *FollowerService) FollowerIterator(params FollowerParams) *social.Iterator[Data] {
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]Data, string, error) {
+		if cursor != "" {
+			params.After = cursor
+		}
+		resp, err := s.GetFollowerContext(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Data, resp.Pagination.Cursor, nil
+	})
+}
+
 // FollowerParams are the params for GetFollower.
 // At minimum, from_id or to_id must be provided for a query to be valid.
 type FollowerParams struct {
 	// FromId. user id. The request returns information about users who are being followed by the FromId user.
-	FromId string `url:"from_id"`
+	FromId string `url:"from_id,omitempty"`
 	// ToId. user ID. The request returns information about users who are following the ToId user.
-	ToId string `url:"to_id"`
+	ToId string `url:"to_id,omitempty"`
 
 	//Optional params
 	// After. Cursor for forward pagination: tells the server where to start fetching the next set of results, in a multi-page response. The cursor value specified here is from the pagination response field of a prior query.
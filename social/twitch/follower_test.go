@@ -0,0 +1,57 @@
+/*
+follower_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitch
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestFollowerIterator checks that the iterator follows the pagination cursor and keeps the other params.
+func TestFollowerIterator(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != FollowerPath || r.URL.Query().Get("to_id") != "42" {
+			http.NotFound(w, r)
+			return
+		}
+		after := r.URL.Query().Get("after")
+		cursors = append(cursors, after)
+		w.Header().Set("Content-Type", "application/json")
+		switch after {
+		case "":
+			w.Write([]byte(`{"total":3,"data":[{"from_id":"1"},{"from_id":"2"}],"pagination":{"cursor":"eyJiIjpudWxsfQ"}}`))
+		default:
+			w.Write([]byte(`{"total":3,"data":[{"from_id":"3"}],"pagination":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	follows, err := c.Follower.FollowerIterator(FollowerParams{ToId: "42", First: 2}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, f := range follows {
+		ids = append(ids, f.FromId)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("followers = %v, want [1 2 3]", ids)
+	}
+	if !reflect.DeepEqual(cursors, []string{"", "eyJiIjpudWxsfQ"}) {
+		t.Errorf("requested cursors %q", cursors)
+	}
+}
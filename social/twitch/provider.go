@@ -0,0 +1,91 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitch
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social"
+)
+
+// Client implements social.Provider.
+var _ social.Provider = (*Client)(nil)
+
+func init() {
+	social.Register(Name, newProvider)
+}
+
+// newProvider builds a Client for the account. The token of the account must be a *oauth2.Token.
+func newProvider(ctx context.Context, account social.Account) (social.Provider, error) {
+	token, ok := account.Token.(*oauth2.Token)
+	if !ok {
+		return nil, social.TokenTypeError(Name, account.Token)
+	}
+	return NewClient(ctx, &account.Credentials, token, account.Options...), nil
+}
+
+// Name returns the provider name.
+func (c *Client) Name() string {
+	return Name
+}
+
+// Capabilities returns the supported methods of the Provider.
+func (c *Client) Capabilities() social.Capabilities {
+	return social.CapFollowers | social.CapFollowing | social.CapRefresh | social.CapRevoke
+}
+
+// FollowersIterator returns an Iterator over the ids of the followers of the authenticated user.
+func (c *Client) FollowersIterator() *social.Iterator[string] {
+	return c.followIterator(func(userId string) FollowerParams {
+		return FollowerParams{ToId: userId}
+	}, func(d Data) string {
+		return d.FromId
+	})
+}
+
+// FollowingIterator returns an Iterator over the ids of the users the authenticated user follows.
+func (c *Client) FollowingIterator() *social.Iterator[string] {
+	return c.followIterator(func(userId string) FollowerParams {
+		return FollowerParams{FromId: userId}
+	}, func(d Data) string {
+		return d.ToId
+	})
+}
+
+// followIterator returns an Iterator over the follow relationships of the authenticated user.
+// The id of the user is requested with the first page.
+func (c *Client) followIterator(params func(userId string) FollowerParams, id func(Data) string) *social.Iterator[string] {
+	var it *social.Iterator[string]
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		if it == nil {
+			u, err := c.User.UserCredentialsContext(ctx, nil)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(u.Data) == 0 {
+				return nil, "", socialErrors.New(socialErrors.ErrNotFound, "twitch: no user for the token")
+			}
+			it = social.MapIterator(c.Follower.FollowerIterator(params(u.Data[0].ID)), id)
+		}
+
+		items, err := it.Next(ctx)
+		if err == social.Done {
+			return nil, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return items, it.Cursor(), nil
+	})
+}
+
+// RefreshContext refreshes the token, see RefreshTokenContext.
+func (c *Client) RefreshContext(ctx context.Context) error {
+	_, err := c.RefreshTokenContext(ctx)
+	return err
+}
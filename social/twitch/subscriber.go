@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package twitch
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 )
@@ -31,13 +32,33 @@ func newSubscriberService(oauth2 *oauth2.OAuth2) *SubscriberService {
 // https://dev.twitch.tv/docs/api/reference#get-broadcaster-subscriptions
 // Required scopes: channel:read:subscriptions
 func (s *SubscriberService) BroadcasterSubscriptions(params SubscriberParams) (*Subscribers, error) {
+	return s.BroadcasterSubscriptionsContext(s.oauth2.Context(), params)
+}
+
+// BroadcasterSubscriptionsContext is like BroadcasterSubscriptions, but sends the request with the given context.
+func (s *SubscriberService) BroadcasterSubscriptionsContext(ctx context.Context, params SubscriberParams) (*Subscribers, error) {
 	subs := new(Subscribers)
 	apiError := new(APIError)
 
-	err := s.oauth2.Get(SubscriberPath, subs, apiError, params)
+	err := s.oauth2.WithContext(ctx).Get(SubscriberPath, subs, apiError, params)
 	return subs, social.CheckError(err)
 }
 
+// BroadcasterSubscriptionsIterator returns an Iterator over all subscriptions of the specified broadcaster.
+// The After cursor of the given params is used as the starting point.
+func (s *SubscriberService) BroadcasterSubscriptionsIterator(params SubscriberParams) *social.Iterator[Broadcaster] {
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]Broadcaster, string, error) {
+		if cursor != "" {
+			params.After = cursor
+		}
+		resp, err := s.BroadcasterSubscriptionsContext(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Broadcaster, resp.Pagination.Cursor, nil
+	})
+}
+
 // SubscriberParams are the params for BroadcasterSubscriptions.
 type SubscriberParams struct {
 	// BroadCasterId. ID of the broadcaster. Must match the User ID in the Bearer token. Required
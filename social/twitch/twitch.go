@@ -8,10 +8,14 @@ package twitch
 
 import (
 	"context"
+	"fmt"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
+	"time"
 )
 
 type Client struct {
@@ -23,6 +27,9 @@ type Client struct {
 }
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "twitch"
+
 	APIBase = "https://api.twitch.tv/"
 
 	ClientHeaderName = "Client-Id"
@@ -32,48 +39,100 @@ const (
 	RevokePath        = "/oauth2/revoke"
 )
 
+// Endpoint is Twitch's OAuth2 endpoint.
+// https://dev.twitch.tv/docs/authentication/getting-tokens-oauth#authorization-code-grant-flow
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://id.twitch.tv/oauth2/authorize",
+	TokenURL:  "https://id.twitch.tv/oauth2/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 // NewClient returns a new Twitter Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token) *Client {
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, opts ...client.Option) *Client {
 	// Twitch requires the client id to be in the header. At least for the endpoints implemented here
-	cl := client.NewHttpClient().Base(APIBase)
-	cl.Add(ClientHeaderName, c.ConsumerKey)
-	auther := oauth2.NewOAuth(ctx, c, token, cl)
-	return &Client{
+	cl := client.NewHttpClient().Base(APIBase).Provider(Name).Options(opts...)
+	cl = cl.Use(client.SetHeader(ClientHeaderName, c.ConsumerKey))
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
+	cli := &Client{
 		oauth2:     auther,
 		User:       newUserService(auther),
 		Subscriber: newSubscriberService(auther),
 		Follower:   newFollowerService(auther),
 	}
+
+	// Refresh the token transparently once the API rejects it
+	auther.SetRefreshTokenSource(oauth2.TokenSourceFunc(cli.refreshedToken))
+	return cli
+}
+
+// OnTokenRefresh sets a callback which is called with the new token once it has been refreshed,
+// automatically or with RefreshToken, e.g. for persisting the token.
+func (c *Client) OnTokenRefresh(fn func(token *oauth2.Token)) {
+	c.oauth2.OnTokenRefresh(fn)
+}
+
+// SetTokenStore sets the TokenStore refreshed tokens are saved into. The tokens are keyed by Name and the given account.
+func (c *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	c.oauth2.SetTokenStore(store, Name, account)
+}
+
+// refreshedToken refreshes the token with the context of the rejected request and returns it.
+// Used as TokenSource of the client.
+func (c *Client) refreshedToken(ctx context.Context) (*oauth2.Token, error) {
+	if _, err := c.RefreshTokenContext(ctx); err != nil {
+		return nil, err
+	}
+	return c.oauth2.Token(), nil
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (c *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return c.oauth2.RateLimit(endpoint)
 }
 
 // RefreshToken a new access token can be generated by supplying the refresh token originally obtained during
 // authorization code exchange.
 // https://dev.twitch.tv/docs/authentication/refresh-tokens
 func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
+	return c.RefreshTokenContext(c.oauth2.Context())
+}
+
+// RefreshTokenContext is like RefreshToken, but sends the request with the given context.
+func (c *Client) RefreshTokenContext(ctx context.Context) (*oauth2.OAuthRefreshResponse, error) {
 	oauthResp := new(OAuth2Response)
 	apiError := new(APIError)
 
 	// Twitch requires the client id and secret to be in the body of the request.
-	rclient := c.oauth2.Client()
-	rclient.AddQuery(struct {
+	rclient := c.oauth2.Client().AddQuery(struct {
 		ClientId     string `url:"client_id"`
 		ClientSecret string `url:"client_secret"`
 	}{
 		ClientId:     c.oauth2.Credentials().ConsumerKey,
 		ClientSecret: c.oauth2.Credentials().ConsumerSecret,
 	})
-	oauth := c.oauth2.NewClient(rclient)
+	oauth := c.oauth2.WithContext(ctx).NewClient(rclient)
 
 	err := oauth.RefreshToken(RefreshRevokeBase, RefreshPath, oauthResp, apiError)
-	c.oauth2.UpdateToken(oauth2.NewToken(oauthResp.AccessToken, oauthResp.RefreshToken))
+	// Keep the current refresh token if the provider did not issue a new one
+	refreshToken := oauthResp.RefreshToken
+	if refreshToken == "" {
+		refreshToken = c.oauth2.Token().RefreshToken
+	}
+	token := &oauth2.Token{
+		Token:        oauthResp.AccessToken,
+		RefreshToken: refreshToken,
+		TokenType:    oauthResp.TokenType,
+		Expiry:       oauth2.ExpiresIn(oauthResp.ExpiresIn),
+		Scopes:       oauthResp.Scope,
+	}
+	if err == nil {
+		err = c.oauth2.UpdateToken(token)
+	}
 	return &oauth2.OAuthRefreshResponse{
-		Token: oauth2.Token{
-			Token:        oauthResp.AccessToken,
-			RefreshToken: oauthResp.RefreshToken,
-		},
+		Token:     *token,
 		TokenType: oauthResp.TokenType,
 		ExpiresIn: oauthResp.ExpiresIn,
-		Scope:     oauthResp.Scope,
+		Scope:     token.Scopes,
 	}, social.CheckError(err)
 }
 
@@ -81,11 +140,15 @@ func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
 // Returns 400 Bad Request if the client ID is valid but the access token is not
 // Returns 404 Not Found if the client ID is not valid.
 func (c *Client) Revoke() error {
+	return c.RevokeContext(c.oauth2.Context())
+}
+
+// RevokeContext is like Revoke, but sends the request with the given context.
+func (c *Client) RevokeContext(ctx context.Context) error {
 	apiError := new(APIError)
 
 	// Twitch requires the client id and secret to be in the body of the request.
-	rclient := c.oauth2.Client()
-	rclient.AddQuery(struct {
+	rclient := c.oauth2.Client().AddQuery(struct {
 		ClientId string `url:"client_id"`
 		Token    string `url:"token"`
 	}{
@@ -93,11 +156,58 @@ func (c *Client) Revoke() error {
 		Token:    c.oauth2.Token().Token,
 	})
 
-	oauth := c.oauth2.NewClient(rclient)
+	oauth := c.oauth2.WithContext(ctx).NewClient(rclient)
 	err := oauth.RevokeToken(RefreshRevokeBase, RevokePath, nil, apiError)
 	return social.CheckError(err)
 }
 
+// GoSocialUser returns the authenticated user. The follower and following counts are the totals of the
+// follows endpoint, so three requests are sent. Partners are marked as verified.
+func (c *Client) GoSocialUser() (*models.SocialUser, error) {
+	return c.GoSocialUserContext(c.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (c *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	u, err := c.User.UserCredentialsContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Data) == 0 {
+		return nil, socialErrors.New(socialErrors.ErrNotFound, "twitch: no user for the token")
+	}
+	user := u.Data[0]
+
+	// Only the total is needed, so a single follow relationship per request is enough
+	followers, err := c.Follower.GetFollowerContext(ctx, FollowerParams{ToId: user.ID, First: 1})
+	if err != nil {
+		return nil, err
+	}
+	following, err := c.Follower.GetFollowerContext(ctx, FollowerParams{FromId: user.ID, First: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	goSocial := models.SocialUser{
+		Provider:  Name,
+		Username:  user.Login,
+		Name:      user.DisplayName,
+		UserId:    user.ID,
+		Verified:  user.BroadcasterType == "partner",
+		AvatarUrl: user.ProfileImageURL,
+		Followers: followers.Total,
+		Following: &following.Total,
+		Url:       fmt.Sprintf("https://www.twitch.tv/%s", user.Login),
+		Bio:       user.Description,
+		Email:     user.Email, // Requires the user:read:email scope
+		BannerUrl: user.OfflineImageURL,
+		CreatedAt: models.OptionalTime(user.CreatedAt),
+		FetchedAt: time.Now(),
+	}
+
+	return &goSocial, nil
+}
+
 type OAuth2Response struct {
 	AccessToken  string   `json:"access_token"`
 	RefreshToken string   `json:"refresh_token"`
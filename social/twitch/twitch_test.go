@@ -0,0 +1,60 @@
+/*
+twitch_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitch
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGoSocialUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == UserPath:
+			w.Write([]byte(`{"data":[{"id":"42","login":"gosocial","display_name":"GoSocial","broadcaster_type":"partner",` +
+				`"description":"Streaming Go","profile_image_url":"https://cdn.twitch.tv/avatar.png",` +
+				`"offline_image_url":"https://cdn.twitch.tv/offline.png","email":"go@social.dev","created_at":"2016-12-14T20:32:28Z"}]}`))
+		case r.URL.Path == FollowerPath && query.Get("to_id") == "42" && query.Get("first") == "1":
+			w.Write([]byte(`{"total":120,"data":[{"from_id":"1"}],"pagination":{"cursor":"a"}}`))
+		case r.URL.Path == FollowerPath && query.Get("from_id") == "42" && query.Get("first") == "1":
+			w.Write([]byte(`{"total":7,"data":[{"to_id":"2"}],"pagination":{"cursor":"b"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	user, err := c.GoSocialUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2016, 12, 14, 20, 32, 28, 0, time.UTC)
+	if user.Provider != Name || user.Username != "gosocial" || user.Name != "GoSocial" || user.UserId != "42" || !user.Verified {
+		t.Errorf("user = %+v", user)
+	}
+	if user.Followers != 120 || user.Following == nil || *user.Following != 7 {
+		t.Errorf("followers, following = %d, %v, want 120, 7", user.Followers, user.Following)
+	}
+	if user.Url != "https://www.twitch.tv/gosocial" || user.Bio != "Streaming Go" || user.Email != "go@social.dev" ||
+		user.AvatarUrl != "https://cdn.twitch.tv/avatar.png" || user.BannerUrl != "https://cdn.twitch.tv/offline.png" {
+		t.Errorf("profile = %+v", user)
+	}
+	if user.CreatedAt == nil || !user.CreatedAt.Equal(created) || user.FetchedAt.IsZero() {
+		t.Errorf("CreatedAt, FetchedAt = %v, %v, want %v", user.CreatedAt, user.FetchedAt, created)
+	}
+}
@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package twitch
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"time"
@@ -32,10 +33,15 @@ func newUserService(oauth2 *oauth2.OAuth2) *UserService {
 // https://dev.twitch.tv/docs/api/reference#get-users
 // Required scopes: -
 func (u *UserService) UserCredentials(params *UserParams) (*User, error) {
+	return u.UserCredentialsContext(u.oauth2.Context(), params)
+}
+
+// UserCredentialsContext is like UserCredentials, but sends the request with the given context.
+func (u *UserService) UserCredentialsContext(ctx context.Context, params *UserParams) (*User, error) {
 	user := new(User)
 	apiError := new(APIError)
 
-	err := u.oauth2.Get(UserPath, user, apiError, params)
+	err := u.oauth2.WithContext(ctx).Get(UserPath, user, apiError, params)
 	return user, social.CheckError(err)
 }
 
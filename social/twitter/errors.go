@@ -9,6 +9,8 @@ package twitter
 import (
 	"fmt"
 	"github.com/emrearmagan/go-social/models/errors"
+	"strconv"
+	"time"
 )
 
 // APIError represents a Spotify API error with its corresponding http StatusCode response
@@ -16,6 +18,10 @@ import (
 type APIError struct {
 	StatusCode int
 	Errors     ErrorDetail
+	// Body is the raw body of an error response which could not be decoded, e.g. an HTML error page
+	Body string
+	// RetryAfter is the wait time requested by the API before sending the request again, if any
+	RetryAfter time.Duration
 }
 
 // ErrorDetail represents the actual error response from the Api
@@ -37,16 +43,16 @@ func (e *APIError) Error() string {
 		err := e.Errors.ErrorStruct[0]
 		return fmt.Sprintf("twitter: %d - %v", err.Code, err.Message)
 	}
+	if e.Body != "" {
+		return fmt.Sprintf("twitter: %d - %v", e.StatusCode, e.Body)
+	}
 	return ""
 }
 
 // Empty returns true if empty. Otherwise, at least 1 error message/code is
 // present and false is returned.
 func (e *APIError) Empty() bool {
-	if len(e.Errors.ErrorStruct) == 0 {
-		return true
-	}
-	return false
+	return len(e.Errors.ErrorStruct) == 0 && e.Body == ""
 }
 
 func (e *APIError) Status() int {
@@ -60,28 +66,63 @@ func (e *APIError) SetStatus(code int) {
 	e.StatusCode = code
 }
 
-// TODO: error handling for not modified
+// SetRawBody keeps the raw body of an error response which could not be decoded.
+func (e *APIError) SetRawBody(body []byte) {
+	e.Body = string(body)
+}
+
+// SetRetryAfter keeps the wait time requested by the API.
+func (e *APIError) SetRetryAfter(d time.Duration) {
+	e.RetryAfter = d
+}
+
 func (e *APIError) ReturnErrorResponse() error {
-	switch e.Status() {
-	case int(invalidCoordinates), int(parameterMissing):
-		return errors.New(errors.ErrBadRequest, e.Error())
-	case int(rateLimitExceeded):
-		return errors.New(errors.ErrRateLimit, e.Error())
-	case int(invalidExpiredToken):
-		return errors.New(errors.ErrInvalidOrExpiredToken, e.Error())
-	case int(internalError):
-		return errors.New(errors.ErrApiError, e.Error())
-	case int(badAuthenticationData):
-		return errors.New(errors.ErrBadAuthenticationData, e.Error())
-	case int(endpointRetired), int(pageDoesNotExists), int(userNotFound), int(noLocation), int(noUserMatches):
-		return errors.New(errors.ErrNotFound, e.Error())
-	case int(userSuspended), int(accountSuspended), int(appNotAllowedToAccessOrDeleteMessage), int(credentialsDontAllowThisResource), int(appCannotPerformWriteActions):
-		return errors.New(errors.ErrForbidden, e.Error())
-	case int(invalidSuspendedApp), int(couldNotAuthenticate), int(notPermitted), int(unableToVerifyCreds), int(notAuthorizedForThisStatus):
-		return errors.New(errors.ErrUnauthorized, e.Error())
+	if len(e.Errors.ErrorStruct) == 0 {
+		return e.socialError(errors.StatusKind(e.StatusCode))
 	}
 
-	return errors.New(errors.ErrUnknownError, e.Error())
+	switch twitterApiCode(e.Status()) {
+	case invalidCoordinates, parameterMissing:
+		return e.socialError(errors.ErrBadRequest)
+	case rateLimitExceeded:
+		return e.socialError(errors.ErrRateLimit)
+	case invalidExpiredToken:
+		return e.socialError(errors.ErrInvalidOrExpiredToken)
+	case internalError:
+		return e.socialError(errors.ErrApiError)
+	case badAuthenticationData:
+		return e.socialError(errors.ErrBadAuthenticationData)
+	case endpointRetired, pageDoesNotExists, userNotFound, noLocation, noUserMatches:
+		return e.socialError(errors.ErrNotFound)
+	case userSuspended, accountSuspended, appNotAllowedToAccessOrDeleteMessage, credentialsDontAllowThisResource, appCannotPerformWriteActions:
+		return e.socialError(errors.ErrForbidden)
+	case invalidSuspendedApp, couldNotAuthenticate, notPermitted, unableToVerifyCreds, notAuthorizedForThisStatus:
+		return e.socialError(errors.ErrUnauthorized)
+	}
+	return e.socialError(errors.StatusKind(e.StatusCode))
+}
+
+// socialError maps the APIError onto a SocialError of the given kind.
+func (e *APIError) socialError(kind error) error {
+	return errors.SocialError{
+		Errors:     kind,
+		Message:    e.Error(),
+		Provider:   Name,
+		StatusCode: e.StatusCode,
+		Code:       e.code(),
+		Retryable:  errors.Retryable(kind, e.StatusCode),
+		RetryAfter: e.RetryAfter,
+		Body:       e.Body,
+		Cause:      e,
+	}
+}
+
+// code returns the code of the first error, if any.
+func (e *APIError) code() string {
+	if len(e.Errors.ErrorStruct) == 0 {
+		return ""
+	}
+	return strconv.Itoa(e.Errors.ErrorStruct[0].Code)
 }
 
 // Most of the error codes from Twitter API.
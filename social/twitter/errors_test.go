@@ -0,0 +1,39 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitter
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "rate limit", Status: 429, ContentType: "application/json", Body: `{"errors":[{"code":88,"message":"Rate limit exceeded"}]}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Code: "88", Retryable: true},
+		{Name: "expired token", Status: 401, ContentType: "application/json", Body: `{"errors":[{"code":89,"message":"Invalid or expired token."}]}`, Kind: socialErrors.ErrInvalidOrExpiredToken, Code: "89"},
+		{Name: "bad authentication data", Status: 400, ContentType: "application/json", Body: `{"errors":[{"code":215,"message":"Bad Authentication data."}]}`, Kind: socialErrors.ErrBadAuthenticationData, Code: "215"},
+		{Name: "could not authenticate", Status: 401, ContentType: "application/json", Body: `{"errors":[{"code":32,"message":"Could not authenticate you."}]}`, Kind: socialErrors.ErrUnauthorized, Code: "32"},
+		{Name: "user suspended", Status: 403, ContentType: "application/json", Body: `{"errors":[{"code":63,"message":"User has been suspended."}]}`, Kind: socialErrors.ErrForbidden, Code: "63"},
+		{Name: "missing parameter", Status: 403, ContentType: "application/json", Body: `{"errors":[{"code":38,"message":"status parameter is missing."}]}`, Kind: socialErrors.ErrBadRequest, Code: "38"},
+		{Name: "user not found", Status: 404, ContentType: "application/json", Body: `{"errors":[{"code":50,"message":"User not found."}]}`, Kind: socialErrors.ErrNotFound, Code: "50"},
+		{Name: "internal error", Status: 500, ContentType: "application/json", Body: `{"errors":[{"code":131,"message":"Internal error"}]}`, Kind: socialErrors.ErrApiError, Code: "131", Retryable: true},
+		{Name: "unknown code", Status: 403, ContentType: "application/json", Body: `{"errors":[{"code":999,"message":"Something"}]}`, Kind: socialErrors.ErrForbidden, Code: "999"},
+		{Name: "forbidden html", Status: 403, ContentType: "text/html", Body: `<html>Forbidden</html>`, Kind: socialErrors.ErrForbidden},
+		{Name: "over capacity html", Status: 503, ContentType: "text/html", Body: `<html>Over capacity</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth1.NewToken("token", "secret"), client.WithBaseURL(baseURL))
+		_, err := c.User.UserCredentialsContext(context.Background(), nil)
+		return err
+	})
+}
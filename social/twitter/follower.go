@@ -7,8 +7,10 @@ Copyright © go-social. All rights reserved.
 package twitter
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/social"
+	"strconv"
 )
 
 const (
@@ -31,23 +33,71 @@ func newFollowerService(oauth1 *oauth1.OAuth1) *FollowerService {
 // FollowerIDs returns a cursored collection of Users following the authorized user.
 // https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/follow-search-get-users/api-reference/get-followers-ids
 func (f *FollowerService) FollowerIDs(params *FollowerIDParams) (*UserFollowerIDs, error) {
+	return f.FollowerIDsContext(f.oauth1.Context(), params)
+}
+
+// FollowerIDsContext is like FollowerIDs, but sends the request with the given context.
+func (f *FollowerService) FollowerIDsContext(ctx context.Context, params *FollowerIDParams) (*UserFollowerIDs, error) {
 	ids := new(UserFollowerIDs)
 	apiError := new(APIError)
 
-	err := f.oauth1.Get(FollowerIdsPath, ids, apiError, params)
+	err := f.oauth1.WithContext(ctx).Get(FollowerIdsPath, ids, apiError, params)
 	return ids, social.CheckError(err)
 }
 
 // FollowingIDs returns a cursored collection of Users the authorized user is following.
 // https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/follow-search-get-users/api-reference/get-friends-ids
 func (f *FollowerService) FollowingIDs(params *FollowerIDParams) (*UserFollowerIDs, error) {
+	return f.FollowingIDsContext(f.oauth1.Context(), params)
+}
+
+// FollowingIDsContext is like FollowingIDs, but sends the request with the given context.
+func (f *FollowerService) FollowingIDsContext(ctx context.Context, params *FollowerIDParams) (*UserFollowerIDs, error) {
 	ids := new(UserFollowerIDs)
 	apiError := new(APIError)
 
-	err := f.oauth1.Get(FollowingIdsPath, ids, apiError, params)
+	err := f.oauth1.WithContext(ctx).Get(FollowingIdsPath, ids, apiError, params)
 	return ids, social.CheckError(err)
 }
 
+// FollowerIDsIterator returns an Iterator over the ids of all Users following the authorized user.
+// The Cursor of the given params is used as the starting point.
+func (f *FollowerService) FollowerIDsIterator(params *FollowerIDParams) *social.Iterator[int64] {
+	return social.NewIterator(f.idPager(params, f.FollowerIDsContext))
+}
+
+// FollowingIDsIterator returns an Iterator over the ids of all Users the authorized user is following.
+// The Cursor of the given params is used as the starting point.
+func (f *FollowerService) FollowingIDsIterator(params *FollowerIDParams) *social.Iterator[int64] {
+	return social.NewIterator(f.idPager(params, f.FollowingIDsContext))
+}
+
+// idPager returns a Pager for the cursored id endpoints. Twitter returns 0 as the next cursor on the last page.
+func (f *FollowerService) idPager(params *FollowerIDParams, fetch func(context.Context, *FollowerIDParams) (*UserFollowerIDs, error)) social.Pager[int64] {
+	p := FollowerIDParams{}
+	if params != nil {
+		p = *params
+	}
+	return func(ctx context.Context, cursor string) ([]int64, string, error) {
+		if cursor != "" {
+			c, err := strconv.ParseInt(cursor, 10, 64)
+			if err != nil {
+				return nil, "", err
+			}
+			p.Cursor = c
+		}
+
+		ids, err := fetch(ctx, &p)
+		if err != nil {
+			return nil, "", err
+		}
+		if ids.NextCursor == 0 {
+			return ids.IDs, "", nil
+		}
+		return ids.IDs, strconv.FormatInt(ids.NextCursor, 10), nil
+	}
+}
+
 // FollowerIDParams are the parameters for IDs
 type FollowerIDParams struct {
 	UserID     int64  `url:"user_id,omitempty"`
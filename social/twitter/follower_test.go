@@ -0,0 +1,69 @@
+/*
+follower_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitter
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestFollowerIDsIterator checks that the iterator follows next_cursor until Twitter returns 0.
+func TestFollowerIDsIterator(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != FollowerIdsPath {
+			http.NotFound(w, r)
+			return
+		}
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		w.Header().Set("Content-Type", "application/json")
+		switch cursor {
+		case "":
+			w.Write([]byte(`{"ids":[1,2],"next_cursor":1500,"next_cursor_str":"1500"}`))
+		case "1500":
+			w.Write([]byte(`{"ids":[3],"next_cursor":0,"next_cursor_str":"0"}`))
+		default:
+			http.Error(w, `{"errors":[{"code":44,"message":"cursor parameter is invalid"}]}`, http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth1.NewToken("token", "secret"), client.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name    string
+		params  *FollowerIDParams
+		want    []int64
+		cursors []string
+	}{
+		{"first page", nil, []int64{1, 2, 3}, []string{"", "1500"}},
+		{"starting cursor", &FollowerIDParams{Cursor: 1500}, []int64{3}, []string{"1500"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursors = nil
+			ids, err := c.Follower.FollowerIDsIterator(tt.params).All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ids = %v, want %v", ids, tt.want)
+			}
+			if !reflect.DeepEqual(cursors, tt.cursors) {
+				t.Errorf("requested cursors %q, want %q", cursors, tt.cursors)
+			}
+		})
+	}
+}
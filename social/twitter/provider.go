@@ -0,0 +1,65 @@
+/*
+provider.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitter
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social"
+	"strconv"
+)
+
+// Client implements social.Provider.
+var _ social.Provider = (*Client)(nil)
+
+func init() {
+	social.Register(Name, newProvider)
+}
+
+// newProvider builds a Client for the account. The token of the account must be a *oauth1.Token.
+func newProvider(ctx context.Context, account social.Account) (social.Provider, error) {
+	token, ok := account.Token.(*oauth1.Token)
+	if !ok {
+		return nil, social.TokenTypeError(Name, account.Token)
+	}
+	return NewClient(ctx, &account.Credentials, token, account.Options...), nil
+}
+
+// Name returns the provider name.
+func (r *Client) Name() string {
+	return Name
+}
+
+// Capabilities returns the supported methods of the Provider. Twitter uses OAuth1, so tokens can neither be refreshed nor revoked.
+func (r *Client) Capabilities() social.Capabilities {
+	return social.CapFollowers | social.CapFollowing
+}
+
+// FollowersIterator returns an Iterator over the ids of the followers of the authenticated user.
+func (r *Client) FollowersIterator() *social.Iterator[string] {
+	return social.MapIterator(r.Follower.FollowerIDsIterator(nil), formatID)
+}
+
+// FollowingIterator returns an Iterator over the ids of the users the authenticated user follows.
+func (r *Client) FollowingIterator() *social.Iterator[string] {
+	return social.MapIterator(r.Follower.FollowingIDsIterator(nil), formatID)
+}
+
+// RefreshContext returns social.ErrUnsupported.
+func (r *Client) RefreshContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
+
+// RevokeContext returns social.ErrUnsupported.
+func (r *Client) RevokeContext(ctx context.Context) error {
+	return social.ErrUnsupported
+}
+
+// formatID formats a numeric user id.
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
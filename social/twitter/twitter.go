@@ -13,35 +13,99 @@ import (
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/social/client"
+	"net/url"
+	"time"
 )
 
 type Client struct {
+	oauth1 *oauth1.OAuth1
+
 	User     *UserService
 	Follower *FollowerService
 }
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "twitter"
+
 	Base = "https://api.twitter.com/"
 )
 
+// Endpoint is Twitter's OAuth1 endpoint.
+// https://developer.twitter.com/en/docs/authentication/oauth-1-0a/obtaining-user-access-tokens
+var Endpoint = oauth1.Endpoint{
+	RequestTokenURL: "https://api.twitter.com/oauth/request_token",
+	AuthorizeURL:    "https://api.twitter.com/oauth/authorize",
+	AccessTokenURL:  "https://api.twitter.com/oauth/access_token",
+}
+
 // NewClient returns a new Twitter Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth1.Token) *Client {
-	cl := client.NewHttpClient().Base(Base)
-	auther := oauth1.NewOAuth(ctx, c, token, cl)
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth1.Token, opts ...client.Option) *Client {
+	cl := client.NewHttpClient().Base(Base).Provider(Name).Options(opts...)
+	auther := oauth1.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
 
 	return &Client{
+		oauth1:   auther,
 		User:     newUserService(auther),
 		Follower: newFollowerService(auther),
 	}
 }
 
+// SetTokenStore sets the TokenStore the token credentials obtained by AccessToken are saved into.
+// The tokens are keyed by Name and the given account.
+func (r *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	r.oauth1.SetTokenStore(store, Name, account)
+}
+
+// Token returns the token credentials the requests are signed with.
+func (r *Client) Token() *oauth1.Token {
+	return r.oauth1.Token()
+}
+
+// RequestToken obtains a request token for the three-legged flow of the Endpoint. See oauth1.OAuth1.RequestToken.
+func (r *Client) RequestToken(callbackURL string) (requestToken, requestSecret string, err error) {
+	return r.RequestTokenContext(r.oauth1.Context(), callbackURL)
+}
+
+// RequestTokenContext is like RequestToken, but sends the request with the given context.
+func (r *Client) RequestTokenContext(ctx context.Context, callbackURL string) (requestToken, requestSecret string, err error) {
+	return r.oauth1.WithContext(ctx).RequestToken(callbackURL)
+}
+
+// AuthorizationURL returns the url the user must be redirected to for authorizing the request token.
+func (r *Client) AuthorizationURL(requestToken string) (*url.URL, error) {
+	return r.oauth1.AuthorizationURL(requestToken)
+}
+
+// AccessToken exchanges the authorized request token for token credentials. The token credentials are used
+// by all services of the client and saved into the TokenStore, if any. See oauth1.OAuth1.AccessToken.
+func (r *Client) AccessToken(requestToken, requestSecret, verifier string) (*oauth1.Token, error) {
+	return r.AccessTokenContext(r.oauth1.Context(), requestToken, requestSecret, verifier)
+}
+
+// AccessTokenContext is like AccessToken, but sends the request with the given context.
+func (r *Client) AccessTokenContext(ctx context.Context, requestToken, requestSecret, verifier string) (*oauth1.Token, error) {
+	return r.oauth1.WithContext(ctx).AccessToken(requestToken, requestSecret, verifier)
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (r *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return r.oauth1.RateLimit(endpoint)
+}
+
 func (r *Client) GoSocialUser() (*models.SocialUser, error) {
-	u, err := r.User.UserCredentials(nil)
+	return r.GoSocialUserContext(r.oauth1.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+func (r *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	u, err := r.User.UserCredentialsContext(ctx, &UserCredentialsParams{IncludeEmail: true})
 	if err != nil {
 		return nil, err
 	}
 
 	goSocial := models.SocialUser{
+		Provider:     Name,
 		Username:     u.ScreenName,
 		Name:         u.Name,
 		UserId:       fmt.Sprintf("%v", u.ID),
@@ -51,6 +115,12 @@ func (r *Client) GoSocialUser() (*models.SocialUser, error) {
 		Followers:    u.FollowersCount,
 		Following:    &u.FriendsCount,
 		Url:          fmt.Sprintf("https://twitter.com/%s", u.ScreenName),
+		Bio:          u.Description,
+		Location:     u.Location,
+		Email:        u.Email,
+		BannerUrl:    u.ProfileBannerURL,
+		CreatedAt:    models.OptionalTime(u.CreatedTime()),
+		FetchedAt:    time.Now(),
 	}
 
 	return &goSocial, nil
@@ -0,0 +1,123 @@
+/*
+twitter_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package twitter
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth1"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// handlerTransport serves all requests with the handler, regardless of their host.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// TestThreeLeggedFlow runs the three-legged flow with a client without token and checks that the
+// obtained token is saved and used by the services of the client.
+func TestThreeLeggedFlow(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get(oauth1.AuthorizationHeaderName)
+		switch r.URL.Host + r.URL.Path {
+		case "api.twitter.com/oauth/request_token":
+			w.Write([]byte("oauth_token=request&oauth_token_secret=request-secret&oauth_callback_confirmed=true"))
+		case "api.twitter.com/oauth/access_token":
+			if !strings.Contains(auth, `oauth_token="request"`) || !strings.Contains(auth, `oauth_verifier="verifier"`) {
+				http.Error(w, "Invalid request token", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("oauth_token=access&oauth_token_secret=access-secret"))
+		case "api.twitter.com" + UserPath:
+			if !strings.Contains(auth, `oauth_token="access"`) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"errors":[{"code":89,"message":"Invalid or expired token."}]}`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1,"screen_name":"go_social"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, nil, client.WithTransport(handlerTransport{handler: handler}))
+	store := oauth.NewMemoryStore()
+	c.SetTokenStore(store, "work")
+
+	requestToken, requestSecret, err := c.RequestToken("https://app.example.com/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := c.AuthorizationURL(requestToken)
+	if err != nil || authURL.String() != Endpoint.AuthorizeURL+"?oauth_token=request" {
+		t.Errorf("AuthorizationURL = %v, %v", authURL, err)
+	}
+
+	token, err := c.AccessToken(requestToken, requestSecret, "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "access" || c.Token() != token {
+		t.Errorf("token = %+v, want the access token set on the client", token)
+	}
+	var saved oauth1.Token
+	if err := store.Load(Name, "work", &saved); err != nil || saved.Token != "access" {
+		t.Errorf("stored token = %+v, %v, want access", saved, err)
+	}
+
+	user, err := c.User.UserCredentials(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ScreenName != "go_social" {
+		t.Errorf("user = %+v", user)
+	}
+}
+
+// TestServiceContext checks that the ...Context methods send the request with the given context and that the
+// other methods fall back to the context of NewClient.
+func TestServiceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"screen_name":"gosocial"}`))
+	}))
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	token := oauth1.NewToken("token", "secret")
+
+	c := NewClient(context.Background(), cred, token, client.WithBaseURL(server.URL))
+	if _, err := c.User.UserCredentialsContext(canceled, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with a canceled call context = %v, want %v", err, context.Canceled)
+	}
+	if _, err := c.User.UserCredentials(nil); err != nil {
+		t.Errorf("error with the context of NewClient = %v, want none", err)
+	}
+
+	c = NewClient(canceled, cred, token, client.WithBaseURL(server.URL))
+	if user, err := c.User.UserCredentialsContext(context.Background(), nil); err != nil || user.ScreenName != "gosocial" {
+		t.Errorf("UserCredentialsContext = %+v, %v, want the user despite the canceled context of NewClient", user, err)
+	}
+	if _, err := c.User.UserCredentials(nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with the canceled context of NewClient = %v, want %v", err, context.Canceled)
+	}
+}
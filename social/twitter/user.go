@@ -7,8 +7,10 @@ Copyright © go-social. All rights reserved.
 package twitter
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth1"
 	"github.com/emrearmagan/go-social/social"
+	"time"
 )
 
 const (
@@ -30,10 +32,15 @@ func newUserService(oauth1 *oauth1.OAuth1) *UserService {
 // UserCredentials returns the authorized user if credentials are valid and returns an error otherwise.
 // https://dev.twitter.com/rest/reference/get/account/verify_credentials
 func (u *UserService) UserCredentials(params *UserCredentialsParams) (*User, error) {
+	return u.UserCredentialsContext(u.oauth1.Context(), params)
+}
+
+// UserCredentialsContext is like UserCredentials, but sends the request with the given context.
+func (u *UserService) UserCredentialsContext(ctx context.Context, params *UserCredentialsParams) (*User, error) {
 	user := new(User)
 	apiError := new(APIError)
 
-	err := u.oauth1.Get(UserPath, user, apiError, params)
+	err := u.oauth1.WithContext(ctx).Get(UserPath, user, apiError, params)
 	return user, social.CheckError(err)
 }
 
@@ -60,6 +67,10 @@ type User struct {
 	CreatedAt                      string      `json:"created_at"`
 	Verified                       bool        `json:"verified"`
 	StatusCount                    int         `json:"statuses_count"`
+	Description                    string      `json:"description"`
+	Location                       string      `json:"location"`
+	Email                          string      `json:"email"` // Only returned with IncludeEmail if the app is permitted to request the email
+	ProfileBannerURL               string      `json:"profile_banner_url"`
 	ProfileBackgroundImageURL      interface{} `json:"profile_background_image_url"`
 	ProfileBackgroundImageURLHTTPS interface{} `json:"profile_background_image_url_https"`
 	ProfileBackgroundTile          bool        `json:"profile_background_tile"`
@@ -67,3 +78,13 @@ type User struct {
 	ProfileImageURLHTTPS           string      `json:"profile_image_url_https"`
 	Suspended                      bool        `json:"suspended"`
 }
+
+// CreatedTime returns the parsed CreatedAt, which has the format "Wed Oct 10 20:19:24 +0000 2018".
+// Returns the zero time if CreatedAt is empty or invalid.
+func (u *User) CreatedTime() time.Time {
+	t, err := time.Parse(time.RubyDate, u.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package youtube
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"time"
@@ -31,13 +32,37 @@ func newChannelService(oauth2 *oauth2.OAuth2) *ChannelService {
 // Channel returns the channel of the authorized user if credentials are valid and returns an error otherwise.
 // Required scopes: https://www.googleapis.com/auth/youtube.readonly
 func (c *ChannelService) Channel(params *ChannelPartParams) (*ChannelResp, error) {
+	return c.ChannelContext(c.oauth2.Context(), params)
+}
+
+// ChannelContext is like Channel, but sends the request with the given context.
+func (c *ChannelService) ChannelContext(ctx context.Context, params *ChannelPartParams) (*ChannelResp, error) {
 	cl := new(ChannelResp)
 	apiError := new(APIError)
 
-	err := c.oauth2.Get(ChannelPath, cl, apiError, params)
+	err := c.oauth2.WithContext(ctx).Get(ChannelPath, cl, apiError, params)
 	return cl, social.CheckError(err)
 }
 
+// ChannelIterator returns an Iterator over all channels matching the given params.
+// The PageToken of the given params is used as the starting point.
+func (c *ChannelService) ChannelIterator(params *ChannelPartParams) *social.Iterator[ChannelItem] {
+	p := ChannelPartParams{}
+	if params != nil {
+		p = *params
+	}
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]ChannelItem, string, error) {
+		if cursor != "" {
+			p.PageToken = cursor
+		}
+		resp, err := c.ChannelContext(ctx, &p)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+}
+
 // ChannelPartParams are the params for Channel information.
 type ChannelPartParams struct {
 	// The part parameter specifies a comma-separated list of one or more channel resource properties that the API response will include.
@@ -56,7 +81,7 @@ type ChannelPartParams struct {
 	//The maxResults parameter specifies the maximum number of items that should be returned in the result set. Acceptable values are 0 to 50, inclusive. The default value is 5.
 	MaxResults uint `url:"maxResults,omitempty"`
 	//The pageToken parameter identifies a specific page in the result set that should be returned. In an API response, the nextPageToken and prevPageToken properties identify other pages that could be retrieved.
-	PageToken uint `url:"pageToken,omitempty"`
+	PageToken string `url:"pageToken,omitempty"`
 }
 
 type ChannelResp struct {
@@ -68,59 +93,67 @@ type ChannelResp struct {
 		TotalResults   int `json:"totalResults"`
 		ResultsPerPage int `json:"resultsPerPage"`
 	} `json:"pageInfo"`
-	Items []struct {
-		Kind    string `json:"kind"`
-		Etag    string `json:"etag"`
-		Id      string `json:"id"`
-		Snippet struct {
-			Title       string    `json:"title"`
-			Description string    `json:"description"`
-			PublishedAt time.Time `json:"publishedAt"`
-			Thumbnails  struct {
-				Default struct {
-					Url    string `json:"url"`
-					Width  int    `json:"width"`
-					Height int    `json:"height"`
-				} `json:"default"`
-				Medium struct {
-					Url    string `json:"url"`
-					Width  int    `json:"width"`
-					Height int    `json:"height"`
-				} `json:"medium"`
-				High struct {
-					Url    string `json:"url"`
-					Width  int    `json:"width"`
-					Height int    `json:"height"`
-				} `json:"high"`
-			} `json:"thumbnails"`
-			Localized struct {
-				Title       string `json:"title"`
-				Description string `json:"description"`
-			} `json:"localized"`
-		} `json:"snippet"`
-		ContentDetails struct {
-			RelatedPlaylists struct {
-				Likes   string `json:"likes"`
-				Uploads string `json:"uploads"`
-			} `json:"relatedPlaylists"`
-		} `json:"contentDetails"`
-		Statistics struct {
-			ViewCount             string `json:"viewCount"`
-			SubscriberCount       string `json:"subscriberCount"`
-			HiddenSubscriberCount bool   `json:"hiddenSubscriberCount"`
-			VideoCount            string `json:"videoCount"`
-		} `json:"statistics"`
-		Status struct {
-			PrivacyStatus     string `json:"privacyStatus"`
-			IsLinked          bool   `json:"isLinked"`
-			LongUploadsStatus string `json:"longUploadsStatus"`
-		} `json:"status"`
-		BrandingSettings struct {
-			Channel struct {
-				Title string `json:"title"`
-			} `json:"channel"`
-		} `json:"brandingSettings"`
-		ContentOwnerDetails struct {
-		} `json:"contentOwnerDetails"`
-	} `json:"items"`
+	Items []ChannelItem `json:"items"`
+}
+
+// ChannelItem represents a single YouTube channel.
+type ChannelItem struct {
+	Kind    string `json:"kind"`
+	Etag    string `json:"etag"`
+	Id      string `json:"id"`
+	Snippet struct {
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		CustomUrl   string    `json:"customUrl"`
+		PublishedAt time.Time `json:"publishedAt"`
+		Country     string    `json:"country"`
+		Thumbnails  struct {
+			Default struct {
+				Url    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"default"`
+			Medium struct {
+				Url    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"medium"`
+			High struct {
+				Url    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"high"`
+		} `json:"thumbnails"`
+		Localized struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"localized"`
+	} `json:"snippet"`
+	ContentDetails struct {
+		RelatedPlaylists struct {
+			Likes   string `json:"likes"`
+			Uploads string `json:"uploads"`
+		} `json:"relatedPlaylists"`
+	} `json:"contentDetails"`
+	Statistics struct {
+		ViewCount             string `json:"viewCount"`
+		SubscriberCount       string `json:"subscriberCount"`
+		HiddenSubscriberCount bool   `json:"hiddenSubscriberCount"`
+		VideoCount            string `json:"videoCount"`
+	} `json:"statistics"`
+	Status struct {
+		PrivacyStatus     string `json:"privacyStatus"`
+		IsLinked          bool   `json:"isLinked"`
+		LongUploadsStatus string `json:"longUploadsStatus"`
+	} `json:"status"`
+	BrandingSettings struct {
+		Channel struct {
+			Title string `json:"title"`
+		} `json:"channel"`
+		Image struct {
+			BannerExternalUrl string `json:"bannerExternalUrl"`
+		} `json:"image"`
+	} `json:"brandingSettings"`
+	ContentOwnerDetails struct {
+	} `json:"contentOwnerDetails"`
 }
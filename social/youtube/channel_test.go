@@ -0,0 +1,57 @@
+/*
+channel_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package youtube
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestChannelIterator checks that the iterator follows nextPageToken and keeps the other params.
+func TestChannelIterator(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ChannelPath || r.URL.Query().Get("part") != "snippet" {
+			http.NotFound(w, r)
+			return
+		}
+		token := r.URL.Query().Get("pageToken")
+		tokens = append(tokens, token)
+		w.Header().Set("Content-Type", "application/json")
+		switch token {
+		case "":
+			w.Write([]byte(`{"nextPageToken":"CAIQAA","items":[{"id":"a"},{"id":"b"}]}`))
+		default:
+			w.Write([]byte(`{"prevPageToken":"CAIQAQ","items":[{"id":"c"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	channels, err := c.Channel.ChannelIterator(&ChannelPartParams{Part: "snippet", MaxResults: 2}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, ch := range channels {
+		ids = append(ids, ch.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+		t.Errorf("channels = %v, want [a b c]", ids)
+	}
+	if !reflect.DeepEqual(tokens, []string{"", "CAIQAA"}) {
+		t.Errorf("requested page tokens %q", tokens)
+	}
+}
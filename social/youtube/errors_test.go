@@ -0,0 +1,34 @@
+/*
+errors_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package youtube
+
+import (
+	"context"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"github.com/emrearmagan/go-social/social/internal/errortest"
+	"testing"
+)
+
+func TestErrorMapping(t *testing.T) {
+	tests := []errortest.Case{
+		{Name: "quota exceeded", Status: 403, ContentType: "application/json", Body: `{"error":{"code":403,"message":"Quota exceeded","errors":[{"message":"Quota exceeded","domain":"youtube.quota","reason":"quotaExceeded"}],"status":"PERMISSION_DENIED"}}`, Kind: socialErrors.ErrRateLimit, Code: "quotaExceeded", Retryable: true},
+		{Name: "rate limit", Status: 403, ContentType: "application/json", Body: `{"error":{"code":403,"message":"Rate limit exceeded","errors":[{"message":"Rate limit exceeded","domain":"usageLimits","reason":"userRateLimitExceeded"}],"status":"PERMISSION_DENIED"}}`, RetryAfter: "30", Kind: socialErrors.ErrRateLimit, Code: "userRateLimitExceeded", Retryable: true},
+		{Name: "forbidden", Status: 403, ContentType: "application/json", Body: `{"error":{"code":403,"message":"Forbidden","errors":[{"message":"Forbidden","domain":"youtube.channel","reason":"forbidden"}],"status":"PERMISSION_DENIED"}}`, Kind: socialErrors.ErrForbidden, Code: "forbidden"},
+		{Name: "invalid credentials", Status: 401, ContentType: "application/json", Body: `{"error":{"code":401,"message":"Invalid Credentials","errors":[{"message":"Invalid Credentials","domain":"global","reason":"authError"}],"status":"UNAUTHENTICATED"}}`, Kind: socialErrors.ErrUnauthorized, Code: "authError"},
+		{Name: "not found", Status: 404, ContentType: "application/json", Body: `{"error":{"code":404,"message":"Channel not found","status":"NOT_FOUND"}}`, Kind: socialErrors.ErrNotFound, Code: "NOT_FOUND"},
+		{Name: "unavailable html", Status: 503, ContentType: "text/html", Body: `<html>Service Unavailable</html>`, Kind: socialErrors.ErrApiError, Retryable: true},
+	}
+	errortest.Run[*APIError](t, Name, tests, func(baseURL string) error {
+		cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+		c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(baseURL))
+		_, err := c.Channel.ChannelContext(context.Background(), nil)
+		return err
+	})
+}
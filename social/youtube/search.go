@@ -7,6 +7,7 @@ Copyright © go-social. All rights reserved.
 package youtube
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"time"
@@ -31,13 +32,37 @@ func newSearchService(oauth2 *oauth2.OAuth2) *SearchService {
 // Search returns the search result of the defined params
 // Required scopes: https://www.googleapis.com/auth/youtube.readonly
 func (c *SearchService) Search(params *SearchParams) (*SearchResp, error) {
+	return c.SearchContext(c.oauth2.Context(), params)
+}
+
+// SearchContext is like Search, but sends the request with the given context.
+func (c *SearchService) SearchContext(ctx context.Context, params *SearchParams) (*SearchResp, error) {
 	cl := new(SearchResp)
 	apiError := new(APIError)
 
-	err := c.oauth2.Get(SearchPath, cl, apiError, params)
+	err := c.oauth2.WithContext(ctx).Get(SearchPath, cl, apiError, params)
 	return cl, social.CheckError(err)
 }
 
+// SearchIterator returns an Iterator over all search results of the defined params.
+// The PageToken of the given params is used as the starting point.
+func (c *SearchService) SearchIterator(params *SearchParams) *social.Iterator[SearchItem] {
+	p := SearchParams{}
+	if params != nil {
+		p = *params
+	}
+	return social.NewIterator(func(ctx context.Context, cursor string) ([]SearchItem, string, error) {
+		if cursor != "" {
+			p.PageToken = cursor
+		}
+		resp, err := c.SearchContext(ctx, &p)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+}
+
 // SearchParams are the params for Searching the YouTube API.
 type SearchParams struct {
 	// ID. Multiple user IDs can be specified. Limit: 100. Optional
@@ -78,32 +103,35 @@ type SearchResp struct {
 		TotalResults   int `json:"totalResults"`
 		ResultsPerPage int `json:"resultsPerPage"`
 	} `json:"pageInfo"`
-	Items []struct {
-		Kind string `json:"kind"`
-		Etag string `json:"etag"`
-		Id   struct {
-			Kind      string `json:"kind"`
-			ChannelId string `json:"channelId"`
-		} `json:"id"`
-		Snippet struct {
-			PublishedAt time.Time `json:"publishedAt"`
-			ChannelId   string    `json:"channelId"`
-			Title       string    `json:"title"`
-			Description string    `json:"description"`
-			Thumbnails  struct {
-				Default struct {
-					Url string `json:"url"`
-				} `json:"default"`
-				Medium struct {
-					Url string `json:"url"`
-				} `json:"medium"`
-				High struct {
-					Url string `json:"url"`
-				} `json:"high"`
-			} `json:"thumbnails"`
-			ChannelTitle         string    `json:"channelTitle"`
-			LiveBroadcastContent string    `json:"liveBroadcastContent"`
-			PublishTime          time.Time `json:"publishTime"`
-		} `json:"snippet"`
-	} `json:"items"`
+	Items []SearchItem `json:"items"`
+}
+
+// SearchItem represents a single search result.
+type SearchItem struct {
+	Kind string `json:"kind"`
+	Etag string `json:"etag"`
+	Id   struct {
+		Kind      string `json:"kind"`
+		ChannelId string `json:"channelId"`
+	} `json:"id"`
+	Snippet struct {
+		PublishedAt time.Time `json:"publishedAt"`
+		ChannelId   string    `json:"channelId"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		Thumbnails  struct {
+			Default struct {
+				Url string `json:"url"`
+			} `json:"default"`
+			Medium struct {
+				Url string `json:"url"`
+			} `json:"medium"`
+			High struct {
+				Url string `json:"url"`
+			} `json:"high"`
+		} `json:"thumbnails"`
+		ChannelTitle         string    `json:"channelTitle"`
+		LiveBroadcastContent string    `json:"liveBroadcastContent"`
+		PublishTime          time.Time `json:"publishTime"`
+	} `json:"snippet"`
 }
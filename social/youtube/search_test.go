@@ -0,0 +1,53 @@
+/*
+search_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package youtube
+
+import (
+	"context"
+	"errors"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestServiceContext checks that the ...Context methods send the request with the given context and that the
+// other methods fall back to the context of NewClient.
+func TestServiceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SearchPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"youtube#searchListResponse","regionCode":"DE"}`))
+	}))
+	defer server.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	params := &SearchParams{Part: "snippet"}
+
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	if _, err := c.Search.SearchContext(canceled, params); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with a canceled call context = %v, want %v", err, context.Canceled)
+	}
+	if _, err := c.Search.Search(params); err != nil {
+		t.Errorf("error with the context of NewClient = %v, want none", err)
+	}
+
+	c = NewClient(canceled, cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	if resp, err := c.Search.SearchContext(context.Background(), params); err != nil || resp.RegionCode != "DE" {
+		t.Errorf("SearchContext = %+v, %v, want the result despite the canceled context of NewClient", resp, err)
+	}
+	if _, err := c.Search.Search(params); !errors.Is(err, context.Canceled) {
+		t.Errorf("error with the canceled context of NewClient = %v, want %v", err, context.Canceled)
+	}
+}
@@ -7,14 +7,17 @@ Copyright © go-social. All rights reserved.
 package youtube
 
 import (
+	"context"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
-	"github.com/emrearmagan/go-social/social/client"
 )
 
 const (
 	UserBase = "https://www.googleapis.com/"
 	UserPath = "/oauth2/v3/userinfo"
+
+	// EmailScope is required for the email of the UserInfo.
+	EmailScope = "https://www.googleapis.com/auth/userinfo.email"
 )
 
 // UserService provides methods for user credentials
@@ -32,12 +35,18 @@ func newUserService(oauth2 *oauth2.OAuth2) *UserService {
 // UserInfo returns the authorized user if credentials are valid and returns an error otherwise.
 // Required scopes: https://www.googleapis.com/auth/userinfo.profile
 func (u *UserService) UserInfo() (*UserInfoResp, error) {
+	return u.UserInfoContext(u.oauth2.Context())
+}
+
+// UserInfoContext is like UserInfo, but sends the request with the given context.
+func (u *UserService) UserInfoContext(ctx context.Context) (*UserInfoResp, error) {
 	user := new(UserInfoResp)
 	apiError := new(APIError)
 
-	// Requires a different base
-	cl := client.NewHttpClient().Base(UserBase)
-	auther := u.oauth2.NewClient(cl)
+	// Requires a different base. The client is copied, so the configured http.Client and a base set
+	// with client.WithBaseURL are kept
+	cl := u.oauth2.Client().Base(UserBase)
+	auther := u.oauth2.WithContext(ctx).NewClient(cl)
 	err := auther.Get(UserPath, user, apiError, nil)
 	return user, social.CheckError(err)
 }
@@ -49,4 +58,7 @@ type UserInfoResp struct {
 	FamilyName string `json:"family_name"`
 	Picture    string `json:"picture"`
 	Locale     string `json:"locale"`
+	// Email and EmailVerified require the EmailScope
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
 }
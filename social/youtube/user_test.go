@@ -0,0 +1,41 @@
+/*
+user_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package youtube
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUserInfoBaseURL checks that the UserInfo is sent to the base set with client.WithBaseURL
+// instead of UserBase.
+func TestUserInfoBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != UserPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sub":"1","name":"go-social"}`))
+	}))
+	defer server.Close()
+
+	cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+	c := NewClient(context.Background(), cred, oauth2.NewToken("token", ""), client.WithBaseURL(server.URL))
+	user, err := c.User.UserInfoContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Sub != "1" || user.Name != "go-social" {
+		t.Errorf("user = %+v", user)
+	}
+}
@@ -8,14 +8,22 @@ package youtube
 
 import (
 	"context"
+	"fmt"
+	"github.com/emrearmagan/go-social/models"
+	socialErrors "github.com/emrearmagan/go-social/models/errors"
 	"github.com/emrearmagan/go-social/oauth"
 	"github.com/emrearmagan/go-social/oauth/oauth2"
 	"github.com/emrearmagan/go-social/social"
 	"github.com/emrearmagan/go-social/social/client"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
+	// Name is the provider name, e.g. used as key in a TokenStore
+	Name = "youtube"
+
 	APIBase = "https://youtube.googleapis.com/"
 
 	ClientHeaderName = "Client-Id"
@@ -25,6 +33,15 @@ const (
 	RevokePath        = "/revoke"
 )
 
+// Endpoint is Google's OAuth2 endpoint.
+// Google only issues a refresh token for offline access: oauth2.SetAuthURLParam("access_type", "offline")
+// https://developers.google.com/identity/protocols/oauth2/web-server
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://accounts.google.com/o/oauth2/v2/auth",
+	TokenURL:  "https://oauth2.googleapis.com/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 type Client struct {
 	oauth2 *oauth2.OAuth2
 
@@ -34,44 +51,83 @@ type Client struct {
 }
 
 // NewClient returns a new Youtube Client.
-func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token) *Client {
+func NewClient(ctx context.Context, c *oauth.Credentials, token *oauth2.Token, opts ...client.Option) *Client {
 	// YouTube requires the client id to be in the header. At least for the endpoints implemented here
-	cl := client.NewHttpClient().Base(APIBase)
-	cl.Add(ClientHeaderName, c.ConsumerKey)
-	auther := oauth2.NewOAuth(ctx, c, token, cl)
-	return &Client{
+	cl := client.NewHttpClient().Base(APIBase).Provider(Name).Options(opts...)
+	cl = cl.Use(client.SetHeader(ClientHeaderName, c.ConsumerKey))
+	auther := oauth2.NewOAuth(ctx, c, token, cl).Endpoint(Endpoint)
+	cli := &Client{
 		oauth2:  auther,
 		User:    newUserService(auther),
 		Channel: newChannelService(auther),
 		Search:  newSearchService(auther),
 	}
+
+	// Refresh the token transparently once the API rejects it
+	auther.SetRefreshTokenSource(oauth2.TokenSourceFunc(cli.refreshedToken))
+	return cli
+}
+
+// OnTokenRefresh sets a callback which is called with the new token once it has been refreshed,
+// automatically or with RefreshToken, e.g. for persisting the token.
+func (c *Client) OnTokenRefresh(fn func(token *oauth2.Token)) {
+	c.oauth2.OnTokenRefresh(fn)
+}
+
+// SetTokenStore sets the TokenStore refreshed tokens are saved into. The tokens are keyed by Name and the given account.
+func (c *Client) SetTokenStore(store oauth.TokenStore, account string) {
+	c.oauth2.SetTokenStore(store, Name, account)
+}
+
+// refreshedToken refreshes the token with the context of the rejected request and returns it.
+// Used as TokenSource of the client.
+func (c *Client) refreshedToken(ctx context.Context) (*oauth2.Token, error) {
+	if _, err := c.RefreshTokenContext(ctx); err != nil {
+		return nil, err
+	}
+	return c.oauth2.Token(), nil
+}
+
+// RateLimit returns the last known rate limit of the given endpoint, e.g. FollowerIdsPath. See client.HttpClient.RateLimit.
+func (c *Client) RateLimit(endpoint string) (client.RateLimit, bool) {
+	return c.oauth2.RateLimit(endpoint)
 }
 
 // RefreshToken a new access token can be generated by supplying the refresh token originally obtained during
 // authorization code exchange.
 // https://developers.google.com/youtube/v3/guides/auth/installed-apps#offline
 func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
+	return c.RefreshTokenContext(c.oauth2.Context())
+}
+
+// RefreshTokenContext is like RefreshToken, but sends the request with the given context.
+func (c *Client) RefreshTokenContext(ctx context.Context) (*oauth2.OAuthRefreshResponse, error) {
 	oauthResp := new(OAuth2Response)
 	apiError := new(APIError)
 
 	// Youtube requires the client id to be in the url of the request.
-	rclient := c.oauth2.Client()
-	rclient.AddQuery(struct {
+	rclient := c.oauth2.Client().AddQuery(struct {
 		ClientId string `url:"client_id"`
 	}{
 		ClientId: c.oauth2.Credentials().ConsumerKey,
 	})
-	oauth := c.oauth2.NewClient(rclient)
+	oauth := c.oauth2.WithContext(ctx).NewClient(rclient)
 	err := oauth.RefreshToken(RefreshRevokeBase, RefreshPath, oauthResp, apiError)
-	c.oauth2.UpdateToken(oauth2.NewToken(oauthResp.AccessToken, c.oauth2.Token().RefreshToken))
+	token := &oauth2.Token{
+		Token:        oauthResp.AccessToken,
+		RefreshToken: c.oauth2.Token().RefreshToken,
+		TokenType:    oauthResp.TokenType,
+		Expiry:       oauth2.ExpiresIn(oauthResp.ExpiresIn),
+		Scopes:       strings.Fields(oauthResp.Scope),
+	}
+	if err == nil {
+		err = c.oauth2.UpdateToken(token)
+	}
 	return &oauth2.OAuthRefreshResponse{
-		Token: oauth2.Token{
-			Token:        oauthResp.AccessToken,
-			RefreshToken: c.oauth2.Token().RefreshToken,
-		},
+		Token:     *token,
 		TokenType: oauthResp.TokenType,
 		ExpiresIn: oauthResp.ExpiresIn,
-		Scope:     strings.Fields(oauthResp.Scope),
+		Scope:     token.Scopes,
 	}, social.CheckError(err)
 }
 
@@ -80,21 +136,94 @@ func (c *Client) RefreshToken() (*oauth2.OAuthRefreshResponse, error) {
 // For error conditions, an HTTP status code 400 is returned along with an error code.
 // https://developers.google.com/youtube/v3/guides/auth/installed-apps#tokenrevoke
 func (c *Client) Revoke() error {
+	return c.RevokeContext(c.oauth2.Context())
+}
+
+// RevokeContext is like Revoke, but sends the request with the given context.
+func (c *Client) RevokeContext(ctx context.Context) error {
 	apiError := new(APIError)
 
 	// YouTube requires the token to be in the url of the request.
 	// The token can be an access token or a refresh token. If the token is an access token and it has a corresponding refresh token, the refresh token will also be revoked.
-	rclient := c.oauth2.Client()
-	rclient.AddQuery(struct {
+	rclient := c.oauth2.Client().AddQuery(struct {
 		Token string `url:"token"`
 	}{
 		Token: c.oauth2.Token().Token,
 	})
-	oauth := c.oauth2.NewClient(rclient)
+	oauth := c.oauth2.WithContext(ctx).NewClient(rclient)
 	err := oauth.RevokeToken(RefreshRevokeBase, RevokePath, nil, apiError)
 	return social.CheckError(err)
 }
 
+// GoSocialUser returns the channel of the authenticated user. The Username is the custom url of the
+// channel, e.g. "@handle", and the followers are the subscribers. YouTube does not provide the channels
+// a user is subscribed to as following count, so Following is nil.
+func (c *Client) GoSocialUser() (*models.SocialUser, error) {
+	return c.GoSocialUserContext(c.oauth2.Context())
+}
+
+// GoSocialUserContext is like GoSocialUser, but sends the requests with the given context.
+// The user is the channel of the authorized user. The email is only requested if the token has been granted the EmailScope.
+// Required scopes: https://www.googleapis.com/auth/youtube.readonly
+func (c *Client) GoSocialUserContext(ctx context.Context) (*models.SocialUser, error) {
+	ch, err := c.Channel.ChannelContext(ctx, &ChannelPartParams{
+		Part: "snippet,statistics,brandingSettings",
+		Mine: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ch.Items) == 0 {
+		return nil, socialErrors.New(socialErrors.ErrNotFound, "youtube: no channel for the token")
+	}
+	item := ch.Items[0]
+
+	// The statistics are returned as strings. The subscriber count is rounded by the API
+	subscribers, _ := strconv.Atoi(item.Statistics.SubscriberCount)
+	videos, _ := strconv.ParseInt(item.Statistics.VideoCount, 10, 64)
+	avatarUrl := item.Snippet.Thumbnails.High.Url
+	if avatarUrl == "" {
+		avatarUrl = item.Snippet.Thumbnails.Default.Url
+	}
+	goSocial := models.SocialUser{
+		Provider:     Name,
+		Username:     item.Snippet.CustomUrl,
+		Name:         item.Snippet.Title,
+		UserId:       item.Id,
+		ContentCount: videos,
+		AvatarUrl:    avatarUrl,
+		Followers:    subscribers,
+		Url:          fmt.Sprintf("https://www.youtube.com/channel/%s", item.Id),
+		Bio:          item.Snippet.Description,
+		Location:     item.Snippet.Country,
+		BannerUrl:    item.BrandingSettings.Image.BannerExternalUrl,
+		CreatedAt:    models.OptionalTime(item.Snippet.PublishedAt),
+		FetchedAt:    time.Now(),
+	}
+
+	if hasScope(c.oauth2.Token().Scopes, EmailScope, "email") {
+		info, err := c.User.UserInfoContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		goSocial.Email = info.Email
+	}
+
+	return &goSocial, nil
+}
+
+// hasScope returns true if one of the given scopes has been granted.
+func hasScope(granted []string, scopes ...string) bool {
+	for _, g := range granted {
+		for _, s := range scopes {
+			if g == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type OAuth2Response struct {
 	AccessToken string `json:"access_token"`
 	ExpiresIn   int    `json:"expires_in"`
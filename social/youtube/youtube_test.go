@@ -0,0 +1,79 @@
+/*
+youtube_test.go
+Created at 18.10.26 by emrearmagan
+Copyright © go-social. All rights reserved.
+*/
+
+package youtube
+
+import (
+	"context"
+	"github.com/emrearmagan/go-social/oauth"
+	"github.com/emrearmagan/go-social/oauth/oauth2"
+	"github.com/emrearmagan/go-social/social/client"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGoSocialUser(t *testing.T) {
+	var userInfoRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case ChannelPath:
+			w.Write([]byte(`{"items":[{"id":"UC42","snippet":{"title":"GoSocial","description":"Go videos","customUrl":"@gosocial",` +
+				`"publishedAt":"2015-03-01T10:00:00Z","country":"DE","thumbnails":{"default":{"url":"https://yt.com/default.jpg"},` +
+				`"high":{"url":"https://yt.com/high.jpg"}}},"statistics":{"subscriberCount":"1200","videoCount":"35"},` +
+				`"brandingSettings":{"image":{"bannerExternalUrl":"https://yt.com/banner.jpg"}}}]}`))
+		case UserPath:
+			atomic.AddInt32(&userInfoRequests, 1)
+			w.Write([]byte(`{"sub":"1","email":"go@social.dev"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		scopes []string
+		email  string
+	}{
+		{"without email scope", nil, ""},
+		{"with email scope", []string{EmailScope}, "go@social.dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&userInfoRequests, 0)
+			cred := &oauth.Credentials{ConsumerKey: "key", ConsumerSecret: "secret"}
+			token := &oauth2.Token{Token: "token", Scopes: tt.scopes}
+			c := NewClient(context.Background(), cred, token, client.WithBaseURL(server.URL))
+			user, err := c.GoSocialUser()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			created := time.Date(2015, 3, 1, 10, 0, 0, 0, time.UTC)
+			if user.Provider != Name || user.Username != "@gosocial" || user.Name != "GoSocial" || user.UserId != "UC42" {
+				t.Errorf("user = %+v", user)
+			}
+			if user.Followers != 1200 || user.Following != nil || user.ContentCount != 35 {
+				t.Errorf("followers, following, content = %d, %v, %d, want 1200, nil, 35", user.Followers, user.Following, user.ContentCount)
+			}
+			if user.Url != "https://www.youtube.com/channel/UC42" || user.Bio != "Go videos" || user.Location != "DE" ||
+				user.AvatarUrl != "https://yt.com/high.jpg" || user.BannerUrl != "https://yt.com/banner.jpg" {
+				t.Errorf("profile = %+v", user)
+			}
+			if user.CreatedAt == nil || !user.CreatedAt.Equal(created) || user.FetchedAt.IsZero() {
+				t.Errorf("CreatedAt, FetchedAt = %v, %v, want %v", user.CreatedAt, user.FetchedAt, created)
+			}
+			// The user info is only requested for the email
+			if n := atomic.LoadInt32(&userInfoRequests); user.Email != tt.email || (n == 1) != (tt.email != "") {
+				t.Errorf("email = %q with %d user info requests, want %q", user.Email, n, tt.email)
+			}
+		})
+	}
+}